@@ -2,6 +2,8 @@ package nakivo
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 )
 
@@ -9,6 +11,9 @@ const (
 	AuthenticationAction = "AuthenticationManagement"
 )
 
+// LoginResultOK is the result reported by the director on a successful login.
+const LoginResultOK = "OK"
+
 type AuthenticationService service
 
 type LoginInfo struct {
@@ -37,64 +42,94 @@ type CanTry struct {
 	FailedAttempts int  `json:"failedAttempts"`
 }
 
+// Login authenticates against the director. The session cookie is kept by the client and, on
+// success, the credentials are remembered to transparently re-authenticate once the session
+// expired.
 func (s *AuthenticationService) Login(ctx context.Context, username, password string, remember bool) (*LoginInfo, *http.Response, error) {
-	request := Request{
-		Action: AuthenticationAction,
-		Method: "login",
-		Data:   []interface{}{username, password, remember},
-		Type:   "rpc",
-		Tid:    1,
-	}
+	creds := &credentials{username: username, password: password, remember: remember}
+
+	s.client.mu.Lock()
+	defer s.client.mu.Unlock()
 
-	req, err := s.client.NewRequest(&request)
+	loginInfo, resp, err := s.login(ctx, creds)
 	if err != nil {
-		return nil, nil, err
+		return loginInfo, resp, err
 	}
+	s.client.credentials = creds
+	s.client.session++
+	return loginInfo, resp, nil
+}
+
+// login performs the login request without touching the session state of the client. The
+// caller must hold the client lock. A rejected login is reported as the error of loginError,
+// along with the login info the director replied with.
+func (s *AuthenticationService) login(ctx context.Context, creds *credentials) (*LoginInfo, *http.Response, error) {
 	var loginInfo LoginInfo
-	r := Response{Data: &loginInfo}
-	resp, err := s.client.Do(ctx, req, &r)
+	params := []interface{}{creds.username, creds.password, creds.remember}
+	_, resp, err := s.client.Call(ctx, AuthenticationAction, "login", params, &loginInfo)
 	if err != nil {
+		if loginInfo.Result != "" {
+			return &loginInfo, resp, err
+		}
 		return nil, resp, err
 	}
 	return &loginInfo, resp, nil
 }
 
-func (s *AuthenticationService) IsLoggedIn(ctx context.Context) (*Response, *http.Response, error) {
-	request := Request{
-		Action: AuthenticationAction,
-		Method: "isLogged",
-		Type:   "rpc",
-		Tid:    1,
+// loginError returns the error of a rejected login, nil if the director accepted it. It is
+// checked within the exchange, so middleware observes rejected logins as failed. The error is
+// an *Error of kind ErrLockout if the director refuses further attempts for now,
+// ErrAuthenticationRequired otherwise.
+func loginError(request *Request, response *Response, resp *http.Response) error {
+	loginInfo, ok := response.Data.(*LoginInfo)
+	if !ok {
+		// the reply was decoded into another type by the caller
+		b, err := json.Marshal(response.Data)
+		if err != nil {
+			return nil
+		}
+		loginInfo = &LoginInfo{}
+		if err := json.Unmarshal(b, loginInfo); err != nil {
+			return nil
+		}
+	}
+	if loginInfo.Result == LoginResultOK {
+		return nil
 	}
 
-	req, err := s.client.NewRequest(&request)
-	if err != nil {
-		return nil, nil, err
+	kind := ErrAuthenticationRequired
+	if !loginInfo.CanTry.IsPossible && loginInfo.CanTry.WaitTimeLeft > 0 {
+		kind = ErrLockout
 	}
-	r := Response{}
-	resp, err := s.client.Do(ctx, req, &r)
-	if err != nil {
-		return nil, resp, err
+	err := &Error{
+		Action: request.Action,
+		Method: request.Method,
+		Tid:    request.Tid,
+		Kind:   kind,
+		Err:    fmt.Errorf("login failed with '%s' (%s)", loginInfo.Result, loginInfo.Reason),
+	}
+	if resp != nil {
+		err.StatusCode = resp.StatusCode
 	}
-	return &r, resp, nil
+	return err
 }
 
+func (s *AuthenticationService) IsLoggedIn(ctx context.Context) (*Response, *http.Response, error) {
+	return s.client.Call(ctx, AuthenticationAction, "isLogged", nil, nil)
+}
+
+// Logout ends the current session. The remembered credentials are discarded, so the client
+// no longer re-authenticates on its own.
 func (s *AuthenticationService) Logout(ctx context.Context) (*Response, *http.Response, error) {
-	request := Request{
-		Action: AuthenticationAction,
-		Method: "logoutCurrentUser",
-		Type:   "rpc",
-		Tid:    1,
-	}
+	s.client.mu.Lock()
+	s.client.credentials = nil
+	s.client.mu.Unlock()
 
-	req, err := s.client.NewRequest(&request)
-	if err != nil {
-		return nil, nil, err
-	}
-	r := Response{}
-	resp, err := s.client.Do(ctx, req, &r)
-	if err != nil {
-		return nil, resp, err
-	}
-	return &r, resp, nil
+	return s.client.Call(ctx, AuthenticationAction, "logoutCurrentUser", nil, nil)
+}
+
+// isLoginRequest reports whether request authenticates a session itself and must therefore
+// never trigger a re-login.
+func isLoginRequest(request *Request) bool {
+	return request.Action == AuthenticationAction && request.Method == "login"
 }
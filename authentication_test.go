@@ -0,0 +1,123 @@
+package nakivo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/peertechde/go-nakivo"
+)
+
+func TestLogin(t *testing.T) {
+	client := fixtureClient(t, "login")
+	ctx := context.Background()
+
+	loginInfo, _, err := client.Authentication.Login(ctx, "admin", "wrong", false)
+	if !errors.Is(err, nakivo.ErrAuthenticationRequired) {
+		t.Fatalf("expected ErrAuthenticationRequired, got %v", err)
+	}
+	if loginInfo == nil || loginInfo.Reason != "INVALID_CREDENTIALS" || loginInfo.CanTry.FailedAttempts != 1 {
+		t.Fatalf("unexpected login info of rejected login %+v", loginInfo)
+	}
+
+	loginInfo, _, err = client.Authentication.Login(ctx, "admin", "secret", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loginInfo.Result != nakivo.LoginResultOK || !loginInfo.UserInfo.IsAdmin {
+		t.Fatalf("unexpected login info %+v", loginInfo)
+	}
+	// the recorder scrubs the name of the user from the fixture
+	if !*record && loginInfo.UserInfo.Name != "SCRUBBED" {
+		t.Fatalf("expected the scrubbed user name, got %q", loginInfo.UserInfo.Name)
+	}
+}
+
+func TestIsLoggedIn(t *testing.T) {
+	client := fixtureClient(t, "is_logged_in")
+	ctx := context.Background()
+
+	_, _, err := client.Authentication.IsLoggedIn(ctx)
+	if !errors.Is(err, nakivo.ErrAuthenticationRequired) {
+		t.Fatalf("expected ErrAuthenticationRequired before login, got %v", err)
+	}
+
+	if _, _, err := client.Authentication.Login(ctx, "admin", "secret", false); err != nil {
+		t.Fatal(err)
+	}
+	resp, _, err := client.Authentication.IsLoggedIn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data != true {
+		t.Fatalf("expected true, got %v", resp.Data)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	client := fixtureClient(t, "logout")
+	ctx := context.Background()
+
+	if _, _, err := client.Authentication.Login(ctx, "admin", "secret", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.Authentication.Logout(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// the credentials are discarded, so the client doesn't log in again
+	_, _, err := client.Authentication.IsLoggedIn(ctx)
+	if !errors.Is(err, nakivo.ErrAuthenticationRequired) {
+		t.Fatalf("expected ErrAuthenticationRequired after logout, got %v", err)
+	}
+}
+
+func TestLoginLockout(t *testing.T) {
+	srv := newDirector()
+	defer srv.Close()
+	srv.SetLockout(2, time.Minute)
+
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if _, _, err := client.Authentication.Login(ctx, "admin", "wrong", false); !errors.Is(err, nakivo.ErrAuthenticationRequired) {
+		t.Fatalf("expected ErrAuthenticationRequired, got %v", err)
+	}
+	loginInfo, _, err := client.Authentication.Login(ctx, "admin", "wrong", false)
+	if !errors.Is(err, nakivo.ErrLockout) {
+		t.Fatalf("expected ErrLockout after the last attempt, got %v", err)
+	}
+	if loginInfo.CanTry.IsPossible || loginInfo.CanTry.WaitTimeLeft <= 0 {
+		t.Errorf("unexpected login info %+v", loginInfo.CanTry)
+	}
+	// valid credentials are refused as well until the lockout ends
+	loginInfo, _, err = client.Authentication.Login(ctx, "admin", "secret", false)
+	if !errors.Is(err, nakivo.ErrLockout) {
+		t.Fatalf("expected ErrLockout, got %v", err)
+	}
+	if loginInfo.Reason != "LOCKED" {
+		t.Errorf("expected reason LOCKED, got %s", loginInfo.Reason)
+	}
+}
+
+func TestReloginLockout(t *testing.T) {
+	srv := newDirector()
+	defer srv.Close()
+	client := loggedIn(t, srv)
+
+	srv.SetLockout(1, time.Minute)
+	srv.AddUser("admin", "changed")
+	srv.ExpireSessions()
+
+	ctx := context.Background()
+	if _, _, err := client.Job.JobInfo(ctx, []int{10}, 0); !errors.Is(err, nakivo.ErrLockout) {
+		t.Fatalf("expected ErrLockout of the login again, got %v", err)
+	}
+	// the rejected credentials are discarded instead of being tried again
+	if _, _, err := client.Job.JobInfo(ctx, []int{10}, 0); !errors.Is(err, nakivo.ErrAuthenticationRequired) {
+		t.Fatalf("expected ErrAuthenticationRequired, got %v", err)
+	}
+}
@@ -0,0 +1,186 @@
+package nakivo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Batch collects several requests which are sent to the director as one JSON array in a single
+// POST. Every request gets a transaction id unique among the batch, which is used to hand each
+// response back to the call it belongs to.
+type Batch struct {
+	client *Client
+	calls  []*BatchCall
+}
+
+// BatchCall is a single request of a batch.
+type BatchCall struct {
+	// Request sent to the director.
+	Request *Request
+
+	// Response of the director. Its Data is decoded into the result passed to Add.
+	Response *Response
+
+	// Err is set if the call failed, e.g. the director reported an error for this particular
+	// request or did not answer it at all.
+	Err error
+
+	// result the data is decoded into. Kept apart since decoding a null data into the response
+	// drops it.
+	result interface{}
+}
+
+func (c *Client) NewBatch() *Batch {
+	return &Batch{client: c}
+}
+
+// Add queues a call of method on action. The data of the response is decoded into result,
+// which may be nil if the result is not of interest.
+func (b *Batch) Add(action, method string, data, result interface{}) *BatchCall {
+	call := &BatchCall{
+		Request: &Request{
+			Action: action,
+			Method: method,
+			Data:   data,
+			Type:   requestType,
+		},
+		Response: &Response{Data: result},
+		result:   result,
+	}
+	b.calls = append(b.calls, call)
+	return call
+}
+
+// Len returns the number of queued calls.
+func (b *Batch) Len() int {
+	return len(b.calls)
+}
+
+// Do sends all queued calls. The returned error is only set if the batch as a whole failed;
+// errors of individual calls are reported through BatchCall.Err.
+//
+// If some calls failed because the session expired and the client knows the credentials of a
+// previous login, it logs in again once and replays these calls.
+func (b *Batch) Do(ctx context.Context) (*http.Response, error) {
+	if len(b.calls) == 0 {
+		return nil, nil
+	}
+	session := b.client.currentSession()
+
+	resp, err := b.client.sendBatch(ctx, b.calls)
+	if err != nil {
+		return resp, err
+	}
+
+	var expired []*BatchCall
+	for _, call := range b.calls {
+		if call.Err != nil && isNotLoggedIn(call.Err) && !isLoginRequest(call.Request) {
+			expired = append(expired, call)
+		}
+	}
+	if len(expired) == 0 {
+		return resp, nil
+	}
+	if err := b.client.relogin(ctx, session); err != nil {
+		if err == errNoCredentials {
+			return resp, nil
+		}
+		return resp, err
+	}
+	for _, call := range expired {
+		*call.Response = Response{Data: call.result}
+		call.Err = nil
+	}
+	return b.client.sendBatch(ctx, expired)
+}
+
+// sendBatch posts calls as a JSON array and demultiplexes the array of responses by their
+// transaction id.
+func (c *Client) sendBatch(ctx context.Context, calls []*BatchCall) (*http.Response, error) {
+	requests := make([]*Request, 0, len(calls))
+	responses := make([]*Response, 0, len(calls))
+	for _, call := range calls {
+		call.Request.Tid = c.nextTid()
+		requests = append(requests, call.Request)
+		responses = append(responses, call.Response)
+	}
+
+	resp, err := c.retry(ctx, requests, func() (*http.Response, error) {
+		for _, call := range calls {
+			*call.Response = Response{Data: call.result}
+			call.Err = nil
+		}
+		req, err := c.newRequest(requests)
+		if err != nil {
+			return nil, err
+		}
+		ex := &Exchange{Requests: requests, Responses: responses, HTTPRequest: req, calls: calls}
+		err = c.handler(ctx, ex)
+		return ex.HTTPResponse, err
+	})
+	if err != nil {
+		return resp, wrapError(err, nil, resp)
+	}
+	return resp, nil
+}
+
+// roundTripBatch sends the batch of ex and hands each response to the call it belongs to.
+func (c *Client) roundTripBatch(ctx context.Context, ex *Exchange) error {
+	var raw json.RawMessage
+	resp, err := c.do(ctx, ex.HTTPRequest, &raw)
+	ex.HTTPResponse = resp
+	if err == nil && resp.StatusCode >= http.StatusBadRequest {
+		err = fmt.Errorf("unexpected http status %s", resp.Status)
+	}
+	if err != nil {
+		return err
+	}
+
+	var responses []json.RawMessage
+	if raw = bytes.TrimSpace(raw); len(raw) > 0 && raw[0] == '{' {
+		// a batch with a single request may be answered with a single response
+		responses = []json.RawMessage{raw}
+	} else if err := json.Unmarshal(raw, &responses); err != nil {
+		return &Error{Kind: ErrMalformedResponse, Err: fmt.Errorf("decode batch response failed (%w)", err)}
+	}
+
+	pending := make(map[string]*BatchCall, len(ex.calls))
+	for _, call := range ex.calls {
+		pending[strconv.Itoa(call.Request.Tid)] = call
+	}
+	for _, r := range responses {
+		var envelope struct {
+			Tid json.RawMessage `json:"tid"`
+		}
+		if err := json.Unmarshal(r, &envelope); err != nil {
+			return &Error{Kind: ErrMalformedResponse, Err: fmt.Errorf("decode batch response failed (%w)", err)}
+		}
+		tid, err := strconv.Unquote(string(envelope.Tid))
+		if err != nil {
+			tid = string(envelope.Tid)
+		}
+		call, ok := pending[tid]
+		if !ok {
+			continue
+		}
+		delete(pending, tid)
+
+		if err := json.Unmarshal(r, call.Response); err != nil {
+			call.Err = wrapError(&Error{Kind: ErrMalformedResponse, Err: fmt.Errorf("decode response failed (%w)", err)}, call.Request, resp)
+			continue
+		}
+		if err := call.Response.Err(); err != nil {
+			call.Err = wrapError(err, call.Request, resp)
+		} else if isLoginRequest(call.Request) {
+			call.Err = loginError(call.Request, call.Response, resp)
+		}
+	}
+	for tid, call := range pending {
+		call.Err = wrapError(&Error{Kind: ErrMalformedResponse, Err: fmt.Errorf("no response for tid %s", tid)}, call.Request, resp)
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+package nakivo_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/peertechde/go-nakivo"
+)
+
+// testClient returns a client of a director served by handler, configured with opts.
+func testClient(t *testing.T, handler http.Handler, opts ...nakivo.Option) *nakivo.Client {
+	t.Helper()
+	srv := httptest.NewTLSServer(handler)
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := nakivo.NewClient(srv.Client(), u.Hostname(), port, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// router returns a handler answering every request of a POST, single or batched, with the
+// response returned by answer. The action, method, tid and type of the response are filled in.
+// Requests answered with nil are left out.
+func router(answer func(request *nakivo.Request) *nakivo.Response) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		batch := bytes.HasPrefix(bytes.TrimSpace(body), []byte("["))
+		var requests []*nakivo.Request
+		if batch {
+			if err := json.Unmarshal(body, &requests); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		} else {
+			request := &nakivo.Request{}
+			if err := json.Unmarshal(body, request); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			requests = append(requests, request)
+		}
+
+		var responses []*nakivo.Response
+		for _, request := range requests {
+			response := answer(request)
+			if response == nil {
+				continue
+			}
+			response.Action, response.Method = request.Action, request.Method
+			response.Tid, response.Type = strconv.Itoa(request.Tid), "rpc"
+			responses = append(responses, response)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case batch:
+			json.NewEncoder(w).Encode(responses)
+		case len(responses) == 1:
+			json.NewEncoder(w).Encode(responses[0])
+		}
+	})
+}
+
+func TestBatch(t *testing.T) {
+	var tids []int
+	client := testClient(t, router(func(request *nakivo.Request) *nakivo.Response {
+		tids = append(tids, request.Tid)
+		switch request.Method {
+		case "echo":
+			return &nakivo.Response{Data: request.Data}
+		case "fail":
+			return &nakivo.Response{Message: "Permission denied", Cause: "AccessDeniedException"}
+		}
+		return &nakivo.Response{Data: true}
+	}))
+	ctx := context.Background()
+
+	var first, second []string
+	batch := client.NewBatch()
+	calls := []*nakivo.BatchCall{
+		batch.Add("Test", "echo", []string{"first"}, &first),
+		batch.Add("Test", "fail", nil, nil),
+		batch.Add("Test", "echo", []string{"second"}, &second),
+	}
+	if _, err := batch.Do(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if calls[0].Err != nil || calls[2].Err != nil {
+		t.Fatalf("unexpected errors %v and %v", calls[0].Err, calls[2].Err)
+	}
+	if !reflect.DeepEqual(first, []string{"first"}) || !reflect.DeepEqual(second, []string{"second"}) {
+		t.Errorf("unexpected results %v and %v", first, second)
+	}
+	var apiErr *nakivo.APIError
+	if !errors.As(calls[1].Err, &apiErr) || apiErr.Cause != "AccessDeniedException" {
+		t.Errorf("expected the error reported for the second call, got %v", calls[1].Err)
+	}
+
+	// transaction ids are unique among batches and single requests
+	if _, _, err := client.Authentication.IsLoggedIn(ctx); err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[int]bool)
+	for _, tid := range tids {
+		if seen[tid] {
+			t.Errorf("tid %d sent twice in %v", tid, tids)
+		}
+		seen[tid] = true
+	}
+}
+
+func TestBatchDemultiplex(t *testing.T) {
+	// answers the requests in reverse order and leaves out the first one
+	client := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requests []nakivo.Request
+		if err := json.NewDecoder(r.Body).Decode(&requests); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var responses []map[string]interface{}
+		for i := len(requests) - 1; i > 0; i-- {
+			responses = append(responses, map[string]interface{}{
+				"action": requests[i].Action,
+				"method": requests[i].Method,
+				"tid":    strconv.Itoa(requests[i].Tid),
+				"type":   "rpc",
+				"data":   requests[i].Method,
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(responses)
+	}))
+
+	results := make([]string, 3)
+	batch := client.NewBatch()
+	calls := []*nakivo.BatchCall{
+		batch.Add("Test", "first", nil, &results[0]),
+		batch.Add("Test", "second", nil, &results[1]),
+		batch.Add("Test", "third", nil, &results[2]),
+	}
+	if _, err := batch.Do(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls[0].Err == nil {
+		t.Errorf("expected an error for the unanswered call")
+	}
+	for i, method := range []string{"second", "third"} {
+		if err := calls[i+1].Err; err != nil {
+			t.Errorf("unexpected error of %s (%v)", method, err)
+		}
+		if results[i+1] != method {
+			t.Errorf("expected the result of %s, got %q", method, results[i+1])
+		}
+	}
+}
+
+func TestBatchRelogin(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		loggedIn bool
+		logins   int
+		replayed []string
+	)
+	client := testClient(t, router(func(request *nakivo.Request) *nakivo.Response {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case request.Method == "login":
+			loggedIn = true
+			logins++
+			return &nakivo.Response{Data: nakivo.LoginInfo{Result: nakivo.LoginResultOK}}
+		case !loggedIn:
+			return &nakivo.Response{Message: "Not logged in", Cause: "NotAuthenticatedException"}
+		}
+		replayed = append(replayed, request.Method)
+		return &nakivo.Response{Data: request.Data}
+	}))
+	ctx := context.Background()
+	if _, _, err := client.Authentication.Login(ctx, "admin", "secret", false); err != nil {
+		t.Fatal(err)
+	}
+	// the session expires
+	mu.Lock()
+	loggedIn = false
+	mu.Unlock()
+
+	var first, second []string
+	batch := client.NewBatch()
+	calls := []*nakivo.BatchCall{
+		batch.Add("Test", "first", []string{"first"}, &first),
+		batch.Add("Test", "second", []string{"second"}, &second),
+	}
+	if _, err := batch.Do(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if logins != 2 {
+		t.Errorf("expected a second login, got %d logins", logins)
+	}
+	if calls[0].Err != nil || calls[1].Err != nil {
+		t.Fatalf("unexpected errors %v and %v", calls[0].Err, calls[1].Err)
+	}
+	if !reflect.DeepEqual(first, []string{"first"}) || !reflect.DeepEqual(second, []string{"second"}) {
+		t.Errorf("unexpected results %v and %v", first, second)
+	}
+	if !reflect.DeepEqual(replayed, []string{"first", "second"}) {
+		t.Errorf("expected both calls to be replayed once, got %v", replayed)
+	}
+}
+
+func TestBatchRejectedLogin(t *testing.T) {
+	var observed []error
+	observe := func(next nakivo.Handler) nakivo.Handler {
+		return func(ctx context.Context, ex *nakivo.Exchange) error {
+			err := next(ctx, ex)
+			for i := range ex.Requests {
+				observed = append(observed, ex.CallErr(i))
+			}
+			return err
+		}
+	}
+	client := testClient(t, router(func(request *nakivo.Request) *nakivo.Response {
+		if request.Method == "login" {
+			return &nakivo.Response{Data: nakivo.LoginInfo{Result: "WRONG_CREDENTIALS", Reason: "Invalid user name or password", CanTry: nakivo.CanTry{IsPossible: true}}}
+		}
+		return &nakivo.Response{Data: true}
+	}), nakivo.WithMiddleware(observe))
+
+	var info nakivo.LoginInfo
+	var loggedIn bool
+	batch := client.NewBatch()
+	login := batch.Add(nakivo.AuthenticationAction, "login", []interface{}{"admin", "wrong", false}, &info)
+	isLogged := batch.Add(nakivo.AuthenticationAction, "isLogged", nil, &loggedIn)
+	if _, err := batch.Do(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var e *nakivo.Error
+	if !errors.Is(login.Err, nakivo.ErrAuthenticationRequired) || !errors.As(login.Err, &e) || e.Tid != login.Request.Tid {
+		t.Errorf("expected the rejected login to fail with ErrAuthenticationRequired, got %v", login.Err)
+	}
+	if info.Result != "WRONG_CREDENTIALS" {
+		t.Errorf("expected the login info to be decoded, got %+v", info)
+	}
+	if isLogged.Err != nil || !loggedIn {
+		t.Errorf("unexpected result of isLogged %t (%v)", loggedIn, isLogged.Err)
+	}
+	// middleware observes the error of the login
+	if len(observed) != 2 || observed[0] != login.Err || observed[1] != nil {
+		t.Errorf("expected middleware to observe the error of the login only, got %v", observed)
+	}
+}
@@ -0,0 +1,51 @@
+package nakivo
+
+import (
+	"context"
+	"net/http"
+)
+
+// requestType is the type of every request sent to the router.
+const requestType = "rpc"
+
+// Call invokes method on action with data as parameters and decodes the data of the reply into
+// result, which may be nil if the result is not of interest. The returned Response is the
+// envelope of the reply.
+//
+// Call is the single path every service goes through: session handling and all other cross
+// cutting behavior of the client apply to it.
+func (c *Client) Call(ctx context.Context, action, method string, data, result interface{}) (*Response, *http.Response, error) {
+	request := Request{
+		Action: action,
+		Method: method,
+		Data:   data,
+		Type:   requestType,
+	}
+
+	r := Response{Data: result}
+	resp, err := c.call(ctx, &request, &r, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &r, resp, nil
+}
+
+// Invoke invokes method on action and decodes the result into a new T. The params are sent as
+// the positional parameters of the method; no params send no data at all.
+//
+// Adding a missing NAKIVO action boils down to declaring its parameters and result type:
+//
+//	jobs, _, err := nakivo.Invoke[nakivo.Jobs](ctx, client, nakivo.JobAction, "getJobInfo", ids, 0)
+func Invoke[T any](ctx context.Context, c *Client, action, method string, params ...interface{}) (*T, *http.Response, error) {
+	var data interface{}
+	if len(params) > 0 {
+		data = params
+	}
+
+	var result T
+	_, resp, err := c.Call(ctx, action, method, data, &result)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &result, resp, nil
+}
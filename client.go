@@ -4,35 +4,76 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"net/http/cookiejar"
 	"net/url"
+	"path"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
-const defaultBaseURL = "https://%s:%d/c/router"
+const (
+	defaultBaseURL   = "https://%s:%d/c/router"
+	defaultUserAgent = "go-nakivo"
+)
+
+var errNoCredentials = errors.New("no credentials known to re-authenticate")
 
-func NewClient(httpClient *http.Client, address string, port int) (*Client, error) {
+func NewClient(httpClient *http.Client, address string, port int, opts ...Option) (*Client, error) {
 	if httpClient == nil {
 		httpClient = &http.Client{}
 	}
-	if address == "" {
-		return nil, fmt.Errorf("unknown address")
-	}
-	if port <= 0 {
-		return nil, fmt.Errorf("unknown port")
-	}
-	u, err := url.Parse(fmt.Sprintf(defaultBaseURL, address, port))
-	if err != nil {
-		return nil, err
+	if httpClient.Jar == nil {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return nil, fmt.Errorf("new cookie jar failed (%s)", err)
+		}
+		// copy the client so the one handed in by the caller is left untouched
+		c := *httpClient
+		c.Jar = jar
+		httpClient = &c
 	}
 	client := &Client{
-		baseURL:    u,
 		httpClient: httpClient,
+		userAgent:  defaultUserAgent,
+		header:     make(http.Header),
 	}
 	client.common.client = client
 	client.Authentication = (*AuthenticationService)(&client.common)
 	client.Job = (*JobService)(&client.common)
 
+	for _, opt := range opts {
+		if err := opt(client); err != nil {
+			return nil, err
+		}
+	}
+	if client.baseURL == nil {
+		if address == "" {
+			return nil, fmt.Errorf("unknown address")
+		}
+		if port <= 0 {
+			return nil, fmt.Errorf("unknown port")
+		}
+		u, err := url.Parse(fmt.Sprintf(defaultBaseURL, address, port))
+		if err != nil {
+			return nil, err
+		}
+		client.baseURL = u
+	}
+	if client.pathPrefix != "" {
+		u := *client.baseURL
+		u.Path = path.Join("/", client.pathPrefix, u.Path)
+		client.baseURL = &u
+	}
+	if err := client.configureTransport(); err != nil {
+		return nil, err
+	}
+	client.handler = chain(client.roundTrip, client.middleware)
 	return client, nil
 }
 
@@ -40,6 +81,33 @@ type Client struct {
 	baseURL    *url.URL
 	httpClient *http.Client
 
+	// mu guards the session state below and serializes re-authentication.
+	mu sync.Mutex
+
+	// credentials of the last successful login, used to re-authenticate once the director
+	// session expired. nil if the client never logged in or logged out.
+	credentials *credentials
+
+	// session is incremented on every successful login and allows concurrent callers to
+	// detect that another goroutine already re-authenticated.
+	session uint64
+
+	// tid is the last transaction id handed out to a request.
+	tid int32
+
+	retryPolicy RetryPolicy
+	tls         *tlsOptions
+	proxy       func(*http.Request) (*url.URL, error)
+	pathPrefix  string
+	userAgent   string
+	header      http.Header
+	timeout     time.Duration
+	middleware  []Middleware
+	logger      *slog.Logger
+
+	// handler sends an exchange through the middleware chain.
+	handler Handler
+
 	common         service
 	Authentication *AuthenticationService
 	Job            *JobService
@@ -49,6 +117,12 @@ type service struct {
 	client *Client
 }
 
+type credentials struct {
+	username string
+	password string
+	remember bool
+}
+
 type Request struct {
 	// Action represents the action that is invoked.
 	Action string `json:"action"`
@@ -107,7 +181,13 @@ func (err *APIError) Error() string {
 }
 
 func (c *Client) NewRequest(request *Request) (*http.Request, error) {
-	r, err := json.Marshal(request)
+	return c.newRequest(request)
+}
+
+// newRequest creates the POST request to the router carrying body, which is either a single
+// request or a batch of requests.
+func (c *Client) newRequest(body interface{}) (*http.Request, error) {
+	r, err := json.Marshal(body)
 	if err != nil {
 		return nil, err
 	}
@@ -115,12 +195,77 @@ func (c *Client) NewRequest(request *Request) (*http.Request, error) {
 	if err != nil {
 		return nil, fmt.Errorf("new http request failed (%s)", err)
 	}
+	for key, values := range c.header {
+		req.Header[key] = append([]string(nil), values...)
+	}
 	req.Header.Set("Content-Type", "application/json")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
 
 	return req, nil
 }
 
+// Do sends req, created by NewRequest, and decodes the reply into response. Like Call, the
+// request is retried, replayed after a re-login if the session expired and passed through the
+// middleware of the client. The headers of req are sent with every attempt. Errors are returned
+// as *Error. An empty body is only accepted if response carries no Data to decode into.
 func (c *Client) Do(ctx context.Context, req *http.Request, response *Response) (*http.Response, error) {
+	request, err := decodeRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	return c.call(ctx, request, response, req.Header)
+}
+
+// decodeRequest returns the request carried by the body of req. The body of req is left
+// untouched if it can be obtained again.
+func decodeRequest(req *http.Request) (*Request, error) {
+	body := req.Body
+	if req.GetBody != nil {
+		var err error
+		if body, err = req.GetBody(); err != nil {
+			return nil, fmt.Errorf("get request body failed (%s)", err)
+		}
+	}
+	if body == nil || body == http.NoBody {
+		return nil, fmt.Errorf("request without body")
+	}
+	defer body.Close()
+
+	var request Request
+	if err := json.NewDecoder(body).Decode(&request); err != nil {
+		return nil, fmt.Errorf("decode request failed (%s)", err)
+	}
+	return &request, nil
+}
+
+// receive sends req and decodes the reply into response, see Do.
+func (c *Client) receive(ctx context.Context, req *http.Request, response *Response) (*http.Response, error) {
+	resp, err := c.do(ctx, req, response)
+	if errors.Is(err, errEmptyBody) && response.Data == nil && resp.StatusCode < http.StatusBadRequest {
+		return resp, nil
+	}
+	if err == nil {
+		err = response.Err()
+	}
+	if err == nil && resp.StatusCode >= http.StatusBadRequest {
+		err = fmt.Errorf("unexpected http status %s", resp.Status)
+	}
+	if err != nil {
+		return resp, wrapError(responseContext(err, response), nil, resp)
+	}
+	return resp, nil
+}
+
+// do sends req and decodes the JSON body of the reply into v. A body which is empty or not JSON
+// is reported as *ResponseBodyError.
+func (c *Client) do(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
+	if _, ok := ctx.Deadline(); !ok && c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
 	req = req.WithContext(ctx)
 
 	resp, err := c.httpClient.Do(req)
@@ -130,16 +275,143 @@ func (c *Client) Do(ctx context.Context, req *http.Request, response *Response)
 			return nil, ctx.Err()
 		default:
 		}
-		return nil, fmt.Errorf("http client do failed (%s)", err)
+		return nil, fmt.Errorf("http client do failed (%w)", err)
 	}
 	defer resp.Body.Close()
 
-	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
-		// todo: handle io.EOF on empty body
-		return resp, err
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp, fmt.Errorf("read response body failed (%w)", err)
 	}
-	if response.Message != "" && response.Cause != "" {
-		return resp, &APIError{Message: response.Message, Where: response.Where, Cause: response.Cause}
+	if err := decodeBody(resp.Header.Get("Content-Type"), body, v); err != nil {
+		return resp, &Error{Kind: ErrMalformedResponse, Err: err}
 	}
 	return resp, nil
 }
+
+// Err returns the error reported by the director in the response as *APIError, nil if the
+// request succeeded.
+func (response *Response) Err() error {
+	if response.Message != "" || response.Cause != "" {
+		return &APIError{Message: response.Message, Where: response.Where, Cause: response.Cause}
+	}
+	return nil
+}
+
+// call sends the request and decodes the reply into response. If the director reports that the
+// session is not authenticated and the client knows the credentials of a previous login, it
+// logs in again once and replays the request. header, if set, is added to every HTTP request.
+func (c *Client) call(ctx context.Context, request *Request, response *Response, header http.Header) (*http.Response, error) {
+	resp, err := c.authenticated(ctx, request, response, header)
+	if err != nil {
+		return resp, wrapError(err, request, resp)
+	}
+	return resp, nil
+}
+
+func (c *Client) authenticated(ctx context.Context, request *Request, response *Response, header http.Header) (*http.Response, error) {
+	if isLoginRequest(request) {
+		return c.send(ctx, request, response, header)
+	}
+	session := c.currentSession()
+	data := response.Data
+
+	resp, err := c.send(ctx, request, response, header)
+	if err == nil || !isNotLoggedIn(err) {
+		return resp, err
+	}
+	if rerr := c.relogin(ctx, session); rerr != nil {
+		if rerr == errNoCredentials {
+			return resp, err
+		}
+		return resp, rerr
+	}
+	*response = Response{Data: data}
+	return c.send(ctx, request, response, header)
+}
+
+// send sends the request, retrying it according to the retry policy of the client.
+func (c *Client) send(ctx context.Context, request *Request, response *Response, header http.Header) (*http.Response, error) {
+	if request.Tid == 0 {
+		request.Tid = c.nextTid()
+	}
+	// decoding a null data drops the result from the response, so keep it for further attempts
+	data := response.Data
+	return c.retry(ctx, []*Request{request}, func() (*http.Response, error) {
+		*response = Response{Data: data}
+
+		req, err := c.NewRequest(request)
+		if err != nil {
+			return nil, err
+		}
+		for key, values := range header {
+			req.Header[key] = append([]string(nil), values...)
+		}
+		ex := &Exchange{Requests: []*Request{request}, Responses: []*Response{response}, HTTPRequest: req}
+		err = c.handler(ctx, ex)
+		return ex.HTTPResponse, err
+	})
+}
+
+// configureTransport applies the options affecting the transport to a clone of the transport
+// of the http client.
+func (c *Client) configureTransport() error {
+	if c.tls == nil && c.proxy == nil {
+		return nil
+	}
+
+	var transport *http.Transport
+	switch t := c.httpClient.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return fmt.Errorf("tls and proxy options require an *http.Transport, got %T", t)
+	}
+	if c.tls != nil {
+		c.configureTLS(transport)
+	}
+	if c.proxy != nil {
+		transport.Proxy = c.proxy
+	}
+
+	httpClient := *c.httpClient
+	httpClient.Transport = transport
+	c.httpClient = &httpClient
+	return nil
+}
+
+// nextTid returns a transaction id which is unique for the lifetime of the client.
+func (c *Client) nextTid() int {
+	return int(atomic.AddInt32(&c.tid, 1))
+}
+
+func (c *Client) currentSession() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.session
+}
+
+// relogin authenticates again with the stored credentials, unless another caller already did
+// so since session was observed.
+func (c *Client) relogin(ctx context.Context, session uint64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session != session {
+		return nil
+	}
+	if c.credentials == nil {
+		return errNoCredentials
+	}
+	if _, _, err := c.Authentication.login(ctx, c.credentials); err != nil {
+		if errors.Is(err, ErrAuthenticationRequired) || errors.Is(err, ErrLockout) {
+			// the credentials are no longer valid, don't keep trying them
+			c.credentials = nil
+		}
+		return err
+	}
+	c.session++
+	return nil
+}
@@ -0,0 +1,152 @@
+package nakivo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/peertechde/go-nakivo"
+	"github.com/peertechde/go-nakivo/nakivotest"
+)
+
+// countLogins returns middleware counting the login exchanges into n.
+func countLogins(n *int) nakivo.Middleware {
+	return func(next nakivo.Handler) nakivo.Handler {
+		return func(ctx context.Context, ex *nakivo.Exchange) error {
+			for _, request := range ex.Requests {
+				if request.Action == nakivo.AuthenticationAction && request.Method == "login" {
+					*n++
+				}
+			}
+			return next(ctx, ex)
+		}
+	}
+}
+
+// loggedIn returns a client of srv logged in as admin.
+func loggedIn(t *testing.T, srv *nakivotest.Server, opts ...nakivo.Option) *nakivo.Client {
+	t.Helper()
+	client, err := srv.Client(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.Authentication.Login(context.Background(), "admin", "secret", false); err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestReloginAfterExpiredSession(t *testing.T) {
+	srv := newDirector()
+	defer srv.Close()
+
+	var logins int
+	client := loggedIn(t, srv, nakivo.WithMiddleware(countLogins(&logins)))
+
+	srv.ExpireSessions()
+	jobs, _, err := client.Job.JobInfo(context.Background(), []int{10}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if logins != 2 {
+		t.Errorf("expected a second login, got %d logins", logins)
+	}
+	// the result of the replayed request is decoded even though the first reply carried no data
+	if len(jobs.Children) != 1 || jobs.Children[0].Name != "Web servers" {
+		t.Errorf("unexpected jobs %+v", jobs.Children)
+	}
+}
+
+func TestReloginOnce(t *testing.T) {
+	srv := newDirector()
+	defer srv.Close()
+
+	var logins int
+	client := loggedIn(t, srv, nakivo.WithMiddleware(countLogins(&logins)))
+
+	// the session stays unauthenticated after the login
+	srv.InjectError(nakivo.JobAction, "getJobInfo", &nakivo.APIError{Message: "Not logged in", Cause: "NotAuthenticatedException"}, 2)
+	_, _, err := client.Job.JobInfo(context.Background(), []int{10}, 0)
+	if !errors.Is(err, nakivo.ErrAuthenticationRequired) {
+		t.Fatalf("expected ErrAuthenticationRequired, got %v", err)
+	}
+	if logins != 2 {
+		t.Errorf("expected a single login again, got %d logins", logins)
+	}
+}
+
+func TestNoReloginWithoutCredentials(t *testing.T) {
+	srv := newDirector()
+	defer srv.Close()
+
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = client.Job.JobInfo(context.Background(), []int{10}, 0)
+	if !errors.Is(err, nakivo.ErrAuthenticationRequired) {
+		t.Fatalf("expected ErrAuthenticationRequired, got %v", err)
+	}
+}
+
+func TestDoRelogin(t *testing.T) {
+	srv := newDirector()
+	defer srv.Close()
+
+	var (
+		logins  int
+		audited []string
+	)
+	audit := func(next nakivo.Handler) nakivo.Handler {
+		return func(ctx context.Context, ex *nakivo.Exchange) error {
+			audited = append(audited, ex.Requests[0].Method+" "+ex.HTTPRequest.Header.Get("X-Audit"))
+			return next(ctx, ex)
+		}
+	}
+	client := loggedIn(t, srv, nakivo.WithMiddleware(countLogins(&logins), audit))
+	audited = nil
+
+	srv.ExpireSessions()
+	req, err := client.NewRequest(&nakivo.Request{Action: nakivo.JobAction, Method: "getJobInfo", Type: "rpc", Data: []interface{}{[]int{10}, 0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Audit", "42")
+	var jobs nakivo.Jobs
+	if _, err := client.Do(context.Background(), req, &nakivo.Response{Data: &jobs}); err != nil {
+		t.Fatal(err)
+	}
+	if logins != 2 {
+		t.Errorf("expected a second login, got %d logins", logins)
+	}
+	if len(jobs.Children) != 1 || jobs.Children[0].Id != 10 {
+		t.Errorf("unexpected jobs %+v", jobs.Children)
+	}
+	// the request passes the middleware with its headers, before and after the login
+	want := []string{"getJobInfo 42", "login ", "getJobInfo 42"}
+	if len(audited) != len(want) {
+		t.Fatalf("expected exchanges %q, got %q", want, audited)
+	}
+	for i := range want {
+		if audited[i] != want[i] {
+			t.Errorf("expected exchanges %q, got %q", want, audited)
+			break
+		}
+	}
+}
+
+func TestDoErrors(t *testing.T) {
+	srv := newDirector()
+	defer srv.Close()
+	client := loggedIn(t, srv)
+
+	req, err := client.NewRequest(&nakivo.Request{Action: nakivo.JobAction, Method: "getNothing", Type: "rpc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Do(context.Background(), req, &nakivo.Response{})
+	var e *nakivo.Error
+	if !errors.As(err, &e) || e.Action != nakivo.JobAction || e.Method != "getNothing" || e.Tid == 0 {
+		t.Errorf("expected *Error of getNothing, got %v", err)
+	}
+}
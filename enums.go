@@ -0,0 +1,628 @@
+package nakivo
+
+// The types below hold the documented values of the enumerated fields of the API. They are
+// plain strings, so values introduced by future versions of the director are kept as they are;
+// Valid reports whether a value is one of the documented ones.
+
+// JobState is the state of a job or of its current or last run.
+type JobState string
+
+const (
+	JobStateWaitingDemand   JobState = "WAITING_DEMAND"
+	JobStateWaitingSchedule JobState = "WAITING_SCHEDULE"
+	JobStateRunning         JobState = "RUNNING"
+	JobStateOK              JobState = "OK"
+	JobStateFailed          JobState = "FAILED"
+	JobStateStopped         JobState = "STOPPED"
+)
+
+var jobStates = []JobState{JobStateWaitingDemand, JobStateWaitingSchedule, JobStateRunning, JobStateOK, JobStateFailed, JobStateStopped}
+
+func (v JobState) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v JobState) Valid() bool {
+	return isOneOf(v, jobStates)
+}
+
+// IsRunning reports whether the job is running.
+func (v JobState) IsRunning() bool {
+	return v == JobStateRunning
+}
+
+// IsWaiting reports whether the job waits to be run on demand or by its schedule.
+func (v JobState) IsWaiting() bool {
+	return v == JobStateWaitingDemand || v == JobStateWaitingSchedule
+}
+
+// IsTerminal reports whether v is the outcome of a finished run.
+func (v JobState) IsTerminal() bool {
+	return v == JobStateOK || v == JobStateFailed || v == JobStateStopped
+}
+
+// JobType is the type of a job.
+type JobType string
+
+const (
+	JobTypeReplication     JobType = "REPLICATION"
+	JobTypeBackup          JobType = "BACKUP"
+	JobTypeRecoveryVMs     JobType = "RECOVERY_VMS"
+	JobTypeRecoveryFiles   JobType = "RECOVERY_FILES"
+	JobTypeRecoveryObjects JobType = "RECOVERY_OBJECTS"
+	JobTypeRecoveryBackups JobType = "RECOVERY_BACKUPS"
+	JobTypeBackupCopy      JobType = "BACKUP_COPY"
+	JobTypeFlashBoot       JobType = "FLASH_BOOT"
+	JobTypeReplicaFailover JobType = "REPLICA_FAILOVER"
+	JobTypeSiteRecovery    JobType = "SITE_RECOVERY"
+)
+
+var jobTypes = []JobType{JobTypeReplication, JobTypeBackup, JobTypeRecoveryVMs, JobTypeRecoveryFiles, JobTypeRecoveryObjects, JobTypeRecoveryBackups, JobTypeBackupCopy, JobTypeFlashBoot, JobTypeReplicaFailover, JobTypeSiteRecovery}
+
+func (v JobType) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v JobType) Valid() bool {
+	return isOneOf(v, jobTypes)
+}
+
+// ObjectState is the state of a source object within a job run.
+type ObjectState string
+
+const (
+	ObjectStateScheduled ObjectState = "SCHEDULED"
+	ObjectStateDemand    ObjectState = "DEMAND"
+	ObjectStateWaiting   ObjectState = "WAITING"
+	ObjectStateRunning   ObjectState = "RUNNING"
+	ObjectStateStopped   ObjectState = "STOPPED"
+	ObjectStateFailed    ObjectState = "FAILED"
+	ObjectStateSucceeded ObjectState = "SUCCEEDED"
+	ObjectStateSkipped   ObjectState = "SKIPPED"
+)
+
+var objectStates = []ObjectState{ObjectStateScheduled, ObjectStateDemand, ObjectStateWaiting, ObjectStateRunning, ObjectStateStopped, ObjectStateFailed, ObjectStateSucceeded, ObjectStateSkipped}
+
+func (v ObjectState) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v ObjectState) Valid() bool {
+	return isOneOf(v, objectStates)
+}
+
+// IsRunning reports whether the object is being processed.
+func (v ObjectState) IsRunning() bool {
+	return v == ObjectStateRunning
+}
+
+// IsTerminal reports whether the processing of the object finished.
+func (v ObjectState) IsTerminal() bool {
+	switch v {
+	case ObjectStateStopped, ObjectStateFailed, ObjectStateSucceeded, ObjectStateSkipped:
+		return true
+	}
+	return false
+}
+
+// PowerState is the power state of a machine.
+type PowerState string
+
+const (
+	PowerStateOn        PowerState = "ON"
+	PowerStateOff       PowerState = "OFF"
+	PowerStateSuspended PowerState = "SUSPENDED"
+	PowerStateUnknown   PowerState = "UNKNOWN"
+)
+
+var powerStates = []PowerState{PowerStateOn, PowerStateOff, PowerStateSuspended, PowerStateUnknown}
+
+func (v PowerState) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v PowerState) Valid() bool {
+	return isOneOf(v, powerStates)
+}
+
+// FlashBootState is the state of a flash boot.
+type FlashBootState string
+
+const (
+	FlashBootStateWaiting    FlashBootState = "WAITING"
+	FlashBootStateStarting   FlashBootState = "STARTING"
+	FlashBootStateRunningVM  FlashBootState = "RUNNING_VM"
+	FlashBootStateFailed     FlashBootState = "FAILED"
+	FlashBootStateDiscarding FlashBootState = "DISCARDING"
+	FlashBootStateDiscarded  FlashBootState = "DISCARDED"
+)
+
+var flashBootStates = []FlashBootState{FlashBootStateWaiting, FlashBootStateStarting, FlashBootStateRunningVM, FlashBootStateFailed, FlashBootStateDiscarding, FlashBootStateDiscarded}
+
+func (v FlashBootState) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v FlashBootState) Valid() bool {
+	return isOneOf(v, flashBootStates)
+}
+
+// ScheduleType is the type of a schedule.
+type ScheduleType string
+
+const (
+	ScheduleTypeDaily         ScheduleType = "DAILY"
+	ScheduleTypePeriodically  ScheduleType = "PERIODICALLY"
+	ScheduleTypeNone          ScheduleType = "NONE"
+	ScheduleTypeMonthlyYearly ScheduleType = "MONTHLY_YEARLY"
+	ScheduleTypeTrigger       ScheduleType = "TRIGGER"
+)
+
+var scheduleTypes = []ScheduleType{ScheduleTypeDaily, ScheduleTypePeriodically, ScheduleTypeNone, ScheduleTypeMonthlyYearly, ScheduleTypeTrigger}
+
+func (v ScheduleType) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v ScheduleType) Valid() bool {
+	return isOneOf(v, scheduleTypes)
+}
+
+// EveryType is the unit of the delay between job runs of a periodic or triggered schedule.
+type EveryType string
+
+const (
+	EveryTypeDay    EveryType = "DAY"
+	EveryTypeHour   EveryType = "HOUR"
+	EveryTypeMinute EveryType = "MINUTE"
+	EveryTypeSecond EveryType = "SECOND"
+)
+
+var everyTypes = []EveryType{EveryTypeDay, EveryTypeHour, EveryTypeMinute, EveryTypeSecond}
+
+func (v EveryType) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v EveryType) Valid() bool {
+	return isOneOf(v, everyTypes)
+}
+
+// MonthlyEveryType is the day of a month a monthly or yearly schedule runs at.
+type MonthlyEveryType string
+
+const (
+	MonthlyEveryTypeFirst  MonthlyEveryType = "FIRST"
+	MonthlyEveryTypeSecond MonthlyEveryType = "SECOND"
+	MonthlyEveryTypeThird  MonthlyEveryType = "THIRD"
+	MonthlyEveryTypeFourth MonthlyEveryType = "FOURTH"
+	MonthlyEveryTypeLast   MonthlyEveryType = "LAST"
+	MonthlyEveryTypeDay    MonthlyEveryType = "DAY"
+)
+
+var monthlyEveryTypes = []MonthlyEveryType{MonthlyEveryTypeFirst, MonthlyEveryTypeSecond, MonthlyEveryTypeThird, MonthlyEveryTypeFourth, MonthlyEveryTypeLast, MonthlyEveryTypeDay}
+
+func (v MonthlyEveryType) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v MonthlyEveryType) Valid() bool {
+	return isOneOf(v, monthlyEveryTypes)
+}
+
+// TriggerRunType is whether a triggered job runs immediately or delayed.
+type TriggerRunType string
+
+const (
+	TriggerRunTypeImmediately TriggerRunType = "IMMEDIATELY"
+	TriggerRunTypeDelayed     TriggerRunType = "DELAYED"
+)
+
+var triggerRunTypes = []TriggerRunType{TriggerRunTypeImmediately, TriggerRunTypeDelayed}
+
+func (v TriggerRunType) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v TriggerRunType) Valid() bool {
+	return isOneOf(v, triggerRunTypes)
+}
+
+// TriggerEvent is the run outcome of the triggering job which triggers a job.
+type TriggerEvent string
+
+const (
+	TriggerEventRunSuccess TriggerEvent = "RUN_SUCCESS"
+	TriggerEventRunFailure TriggerEvent = "RUN_FAILURE"
+	TriggerEventRunStop    TriggerEvent = "RUN_STOP"
+)
+
+var triggerEvents = []TriggerEvent{TriggerEventRunSuccess, TriggerEventRunFailure, TriggerEventRunStop}
+
+func (v TriggerEvent) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v TriggerEvent) Valid() bool {
+	return isOneOf(v, triggerEvents)
+}
+
+// FullBackupMode is how full backups are created.
+type FullBackupMode string
+
+const (
+	FullBackupModeSynthetic FullBackupMode = "SYNTHETIC"
+	FullBackupModeActive    FullBackupMode = "ACTIVE"
+)
+
+var fullBackupModes = []FullBackupMode{FullBackupModeSynthetic, FullBackupModeActive}
+
+func (v FullBackupMode) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v FullBackupMode) Valid() bool {
+	return isOneOf(v, fullBackupModes)
+}
+
+// FullBackupRunSettingsType is when full backups are run.
+type FullBackupRunSettingsType string
+
+const (
+	FullBackupRunSettingsTypeAlways       FullBackupRunSettingsType = "ALWAYS"
+	FullBackupRunSettingsTypeEvery        FullBackupRunSettingsType = "EVERY"
+	FullBackupRunSettingsTypeEvery2nd     FullBackupRunSettingsType = "EVERY2ND"
+	FullBackupRunSettingsTypeFirst        FullBackupRunSettingsType = "FIRST"
+	FullBackupRunSettingsTypeSecond       FullBackupRunSettingsType = "SECOND"
+	FullBackupRunSettingsTypeThird        FullBackupRunSettingsType = "THIRD"
+	FullBackupRunSettingsTypeFourth       FullBackupRunSettingsType = "FOURTH"
+	FullBackupRunSettingsTypeLast         FullBackupRunSettingsType = "LAST"
+	FullBackupRunSettingsTypeDay          FullBackupRunSettingsType = "DAY"
+	FullBackupRunSettingsTypeEveryJobRuns FullBackupRunSettingsType = "EVERY_JOB_RUNS"
+)
+
+var fullBackupRunSettingsTypes = []FullBackupRunSettingsType{FullBackupRunSettingsTypeAlways, FullBackupRunSettingsTypeEvery, FullBackupRunSettingsTypeEvery2nd, FullBackupRunSettingsTypeFirst, FullBackupRunSettingsTypeSecond, FullBackupRunSettingsTypeThird, FullBackupRunSettingsTypeFourth, FullBackupRunSettingsTypeLast, FullBackupRunSettingsTypeDay, FullBackupRunSettingsTypeEveryJobRuns}
+
+func (v FullBackupRunSettingsType) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v FullBackupRunSettingsType) Valid() bool {
+	return isOneOf(v, fullBackupRunSettingsTypes)
+}
+
+// RecoveryTimeObjectiveType is the unit of a recovery time objective.
+type RecoveryTimeObjectiveType string
+
+const (
+	RecoveryTimeObjectiveTypeMinute RecoveryTimeObjectiveType = "MINUTE"
+	RecoveryTimeObjectiveTypeHour   RecoveryTimeObjectiveType = "HOUR"
+)
+
+var recoveryTimeObjectiveTypes = []RecoveryTimeObjectiveType{RecoveryTimeObjectiveTypeMinute, RecoveryTimeObjectiveTypeHour}
+
+func (v RecoveryTimeObjectiveType) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v RecoveryTimeObjectiveType) Valid() bool {
+	return isOneOf(v, recoveryTimeObjectiveTypes)
+}
+
+// SiteRecoveryRunType is the type of a site recovery run.
+type SiteRecoveryRunType string
+
+const (
+	SiteRecoveryRunTypeTest SiteRecoveryRunType = "TEST"
+	SiteRecoveryRunTypeRun  SiteRecoveryRunType = "RUN"
+)
+
+var siteRecoveryRunTypes = []SiteRecoveryRunType{SiteRecoveryRunTypeTest, SiteRecoveryRunTypeRun}
+
+func (v SiteRecoveryRunType) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v SiteRecoveryRunType) Valid() bool {
+	return isOneOf(v, siteRecoveryRunTypes)
+}
+
+// FailoverType is the type of a failover.
+type FailoverType string
+
+const (
+	FailoverTypePlanned   FailoverType = "PLANNED_FAILOVER"
+	FailoverTypeEmergency FailoverType = "EMERGENCY_FAILOVER"
+)
+
+var failoverTypes = []FailoverType{FailoverTypePlanned, FailoverTypeEmergency}
+
+func (v FailoverType) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v FailoverType) Valid() bool {
+	return isOneOf(v, failoverTypes)
+}
+
+// LogTruncationMode is when Microsoft SQL Server or Exchange logs are truncated.
+type LogTruncationMode string
+
+const (
+	LogTruncationModeNone       LogTruncationMode = "NONE"
+	LogTruncationModeAlways     LogTruncationMode = "ALWAYS"
+	LogTruncationModeJobSuccess LogTruncationMode = "JOB_SUCCESS"
+)
+
+var logTruncationModes = []LogTruncationMode{LogTruncationModeNone, LogTruncationModeAlways, LogTruncationModeJobSuccess}
+
+func (v LogTruncationMode) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v LogTruncationMode) Valid() bool {
+	return isOneOf(v, logTruncationModes)
+}
+
+// DifferentialTrackingMode is the method used for forever-incremental backups.
+type DifferentialTrackingMode string
+
+const (
+	DifferentialTrackingModeNone        DifferentialTrackingMode = "NONE"
+	DifferentialTrackingModeHypervisor  DifferentialTrackingMode = "HYPERVISOR"
+	DifferentialTrackingModeDoubleCheck DifferentialTrackingMode = "DOUBLE_CHECK"
+	DifferentialTrackingModeProprietary DifferentialTrackingMode = "PROPRIETARY"
+)
+
+var differentialTrackingModes = []DifferentialTrackingMode{DifferentialTrackingModeNone, DifferentialTrackingModeHypervisor, DifferentialTrackingModeDoubleCheck, DifferentialTrackingModeProprietary}
+
+func (v DifferentialTrackingMode) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v DifferentialTrackingMode) Valid() bool {
+	return isOneOf(v, differentialTrackingModes)
+}
+
+// ScriptExecutionMode is whether pre- or post-job scripts are executed.
+type ScriptExecutionMode string
+
+const (
+	ScriptExecutionModeNever  ScriptExecutionMode = "NEVER"
+	ScriptExecutionModeAlways ScriptExecutionMode = "ALWAYS"
+)
+
+var scriptExecutionModes = []ScriptExecutionMode{ScriptExecutionModeNever, ScriptExecutionModeAlways}
+
+func (v ScriptExecutionMode) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v ScriptExecutionMode) Valid() bool {
+	return isOneOf(v, scriptExecutionModes)
+}
+
+// ScriptBehavior is whether a job waits for its pre- or post-job script to finish.
+type ScriptBehavior string
+
+const (
+	ScriptBehaviorNone    ScriptBehavior = "NONE"
+	ScriptBehaviorWait    ScriptBehavior = "WAIT"
+	ScriptBehaviorProceed ScriptBehavior = "PROCEED"
+)
+
+var scriptBehaviors = []ScriptBehavior{ScriptBehaviorNone, ScriptBehaviorWait, ScriptBehaviorProceed}
+
+func (v ScriptBehavior) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v ScriptBehavior) Valid() bool {
+	return isOneOf(v, scriptBehaviors)
+}
+
+// ScriptErrorMode is how a job behaves if its pre- or post-job script fails.
+type ScriptErrorMode string
+
+const (
+	ScriptErrorModeNone ScriptErrorMode = "NONE"
+	ScriptErrorModeFail ScriptErrorMode = "FAIL"
+	ScriptErrorModeSkip ScriptErrorMode = "SKIP"
+)
+
+var scriptErrorModes = []ScriptErrorMode{ScriptErrorModeNone, ScriptErrorModeFail, ScriptErrorModeSkip}
+
+func (v ScriptErrorMode) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v ScriptErrorMode) Valid() bool {
+	return isOneOf(v, scriptErrorModes)
+}
+
+// ThinDiskMode is whether replicas use thin disks.
+type ThinDiskMode string
+
+const (
+	ThinDiskModeAuto      ThinDiskMode = "AUTO"
+	ThinDiskModeForceThin ThinDiskMode = "FORCE_THIN"
+)
+
+var thinDiskModes = []ThinDiskMode{ThinDiskModeAuto, ThinDiskModeForceThin}
+
+func (v ThinDiskMode) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v ThinDiskMode) Valid() bool {
+	return isOneOf(v, thinDiskModes)
+}
+
+// EbsVolumeMode is the type of EBS volumes of AWS jobs.
+type EbsVolumeMode string
+
+const (
+	EbsVolumeModeAuto          EbsVolumeMode = "AUTO"
+	EbsVolumeModeForceMagnetic EbsVolumeMode = "FORCE_MAGNETIC"
+)
+
+var ebsVolumeModes = []EbsVolumeMode{EbsVolumeModeAuto, EbsVolumeModeForceMagnetic}
+
+func (v EbsVolumeMode) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v EbsVolumeMode) Valid() bool {
+	return isOneOf(v, ebsVolumeModes)
+}
+
+// NetworkAccelerationMode is the network acceleration of a job.
+type NetworkAccelerationMode string
+
+const (
+	NetworkAccelerationModeNone   NetworkAccelerationMode = "NONE"
+	NetworkAccelerationModeAuto   NetworkAccelerationMode = "AUTO"
+	NetworkAccelerationModeFast   NetworkAccelerationMode = "FAST"
+	NetworkAccelerationModeMedium NetworkAccelerationMode = "MEDIUM"
+	NetworkAccelerationModeBest   NetworkAccelerationMode = "BEST"
+)
+
+var networkAccelerationModes = []NetworkAccelerationMode{NetworkAccelerationModeNone, NetworkAccelerationModeAuto, NetworkAccelerationModeFast, NetworkAccelerationModeMedium, NetworkAccelerationModeBest}
+
+func (v NetworkAccelerationMode) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v NetworkAccelerationMode) Valid() bool {
+	return isOneOf(v, networkAccelerationModes)
+}
+
+// EncryptionMode is the network encryption of a job.
+type EncryptionMode string
+
+const (
+	EncryptionModeNone   EncryptionMode = "NONE"
+	EncryptionModeNormal EncryptionMode = "NORMAL"
+)
+
+var encryptionModes = []EncryptionMode{EncryptionModeNone, EncryptionModeNormal}
+
+func (v EncryptionMode) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v EncryptionMode) Valid() bool {
+	return isOneOf(v, encryptionModes)
+}
+
+// ApplicationAwareMode is the application-aware processing of a job.
+type ApplicationAwareMode string
+
+const (
+	ApplicationAwareModeNone            ApplicationAwareMode = "NONE"
+	ApplicationAwareModeVSSIgnoreErrors ApplicationAwareMode = "VSS_IGNORE_ERRORS"
+	ApplicationAwareModeVSSFailOnErrors ApplicationAwareMode = "VSS_FAIL_ON_ERRORS"
+)
+
+var applicationAwareModes = []ApplicationAwareMode{ApplicationAwareModeNone, ApplicationAwareModeVSSIgnoreErrors, ApplicationAwareModeVSSFailOnErrors}
+
+func (v ApplicationAwareMode) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v ApplicationAwareMode) Valid() bool {
+	return isOneOf(v, applicationAwareModes)
+}
+
+// RecoveryType is the type of a recovery.
+type RecoveryType string
+
+const (
+	RecoveryTypeSynthetic  RecoveryType = "SYNTHETIC"
+	RecoveryTypeProduction RecoveryType = "PRODUCTION"
+)
+
+var recoveryTypes = []RecoveryType{RecoveryTypeSynthetic, RecoveryTypeProduction}
+
+func (v RecoveryType) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v RecoveryType) Valid() bool {
+	return isOneOf(v, recoveryTypes)
+}
+
+// TransporterMode is the mode of data transfer.
+type TransporterMode string
+
+const (
+	TransporterModeAuto   TransporterMode = "AUTO"
+	TransporterModeSAN    TransporterMode = "SAN"
+	TransporterModeLAN    TransporterMode = "LAN"
+	TransporterModeHotAdd TransporterMode = "HOT_ADD"
+)
+
+var transporterModes = []TransporterMode{TransporterModeAuto, TransporterModeSAN, TransporterModeLAN, TransporterModeHotAdd}
+
+func (v TransporterMode) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v TransporterMode) Valid() bool {
+	return isOneOf(v, transporterModes)
+}
+
+// ScreenshotVerificationMode is whether recovered machines are verified by screenshots.
+type ScreenshotVerificationMode string
+
+const (
+	ScreenshotVerificationModeNever  ScreenshotVerificationMode = "NEVER"
+	ScreenshotVerificationModeAlways ScreenshotVerificationMode = "ALWAYS"
+)
+
+var screenshotVerificationModes = []ScreenshotVerificationMode{ScreenshotVerificationModeNever, ScreenshotVerificationModeAlways}
+
+func (v ScreenshotVerificationMode) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v ScreenshotVerificationMode) Valid() bool {
+	return isOneOf(v, screenshotVerificationModes)
+}
+
+func isOneOf[T comparable](v T, values []T) bool {
+	for _, value := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
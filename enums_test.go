@@ -0,0 +1,138 @@
+package nakivo_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/peertechde/go-nakivo"
+)
+
+func TestJobState(t *testing.T) {
+	tests := []struct {
+		state                             nakivo.JobState
+		valid, running, waiting, terminal bool
+	}{
+		{nakivo.JobStateWaitingDemand, true, false, true, false},
+		{nakivo.JobStateWaitingSchedule, true, false, true, false},
+		{nakivo.JobStateRunning, true, true, false, false},
+		{nakivo.JobStateOK, true, false, false, true},
+		{nakivo.JobStateFailed, true, false, false, true},
+		{nakivo.JobStateStopped, true, false, false, true},
+		{"PAUSED", false, false, false, false},
+		{"running", false, false, false, false},
+		{"", false, false, false, false},
+	}
+	for _, tt := range tests {
+		if got := tt.state.Valid(); got != tt.valid {
+			t.Errorf("%q: expected Valid %t, got %t", tt.state, tt.valid, got)
+		}
+		if got := tt.state.IsRunning(); got != tt.running {
+			t.Errorf("%q: expected IsRunning %t, got %t", tt.state, tt.running, got)
+		}
+		if got := tt.state.IsWaiting(); got != tt.waiting {
+			t.Errorf("%q: expected IsWaiting %t, got %t", tt.state, tt.waiting, got)
+		}
+		if got := tt.state.IsTerminal(); got != tt.terminal {
+			t.Errorf("%q: expected IsTerminal %t, got %t", tt.state, tt.terminal, got)
+		}
+	}
+}
+
+func TestObjectState(t *testing.T) {
+	tests := []struct {
+		state                    nakivo.ObjectState
+		valid, running, terminal bool
+	}{
+		{nakivo.ObjectStateScheduled, true, false, false},
+		{nakivo.ObjectStateDemand, true, false, false},
+		{nakivo.ObjectStateWaiting, true, false, false},
+		{nakivo.ObjectStateRunning, true, true, false},
+		{nakivo.ObjectStateStopped, true, false, true},
+		{nakivo.ObjectStateFailed, true, false, true},
+		{nakivo.ObjectStateSucceeded, true, false, true},
+		{nakivo.ObjectStateSkipped, true, false, true},
+		{"QUEUED", false, false, false},
+		{"", false, false, false},
+	}
+	for _, tt := range tests {
+		if got := tt.state.Valid(); got != tt.valid {
+			t.Errorf("%q: expected Valid %t, got %t", tt.state, tt.valid, got)
+		}
+		if got := tt.state.IsRunning(); got != tt.running {
+			t.Errorf("%q: expected IsRunning %t, got %t", tt.state, tt.running, got)
+		}
+		if got := tt.state.IsTerminal(); got != tt.terminal {
+			t.Errorf("%q: expected IsTerminal %t, got %t", tt.state, tt.terminal, got)
+		}
+	}
+}
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		value interface{ Valid() bool }
+		valid bool
+	}{
+		{nakivo.JobTypeBackup, true},
+		{nakivo.JobType("BACKUP_TO_TAPE"), false},
+		{nakivo.PowerStateUnknown, true},
+		{nakivo.PowerState("HIBERNATED"), false},
+		{nakivo.FlashBootStateRunningVM, true},
+		{nakivo.FlashBootState("RUNNING"), false},
+		{nakivo.ScheduleTypeMonthlyYearly, true},
+		{nakivo.ScheduleType("WEEKLY"), false},
+		{nakivo.EveryTypeSecond, true},
+		{nakivo.EveryType("WEEK"), false},
+		{nakivo.TriggerEventRunStop, true},
+		{nakivo.TriggerEvent(""), false},
+	}
+	for _, tt := range tests {
+		if got := tt.value.Valid(); got != tt.valid {
+			t.Errorf("%v: expected Valid %t, got %t", tt.value, tt.valid, got)
+		}
+	}
+}
+
+func TestUnknownValues(t *testing.T) {
+	data := `{
+		"id": 10,
+		"jobType": "BACKUP_TO_TAPE",
+		"crState": "PAUSED",
+		"lrState": "OK",
+		"objects": [{"vid": "vm-1", "crState": "QUEUED", "lrState": "SUCCEEDED"}],
+		"schedules": [{"type": "WEEKLY", "everyType": "WEEK"}]
+	}`
+	var job nakivo.Job
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		t.Fatal(err)
+	}
+
+	// undocumented values are kept as they are, but aren't valid
+	if job.CrState != "PAUSED" || job.CrState.Valid() || job.CrState.IsRunning() || job.CrState.IsWaiting() || job.CrState.IsTerminal() {
+		t.Errorf("unexpected crState %q", job.CrState)
+	}
+	if job.JobType != "BACKUP_TO_TAPE" || job.JobType.Valid() {
+		t.Errorf("unexpected jobType %q", job.JobType)
+	}
+	if job.LrState != nakivo.JobStateOK {
+		t.Errorf("unexpected lrState %q", job.LrState)
+	}
+	if len(job.Objects) != 1 || job.Objects[0].CrState != "QUEUED" || job.Objects[0].CrState.Valid() || job.Objects[0].LrState != nakivo.ObjectStateSucceeded {
+		t.Errorf("unexpected objects %+v", job.Objects)
+	}
+	if len(job.Schedules) != 1 || job.Schedules[0].Type != "WEEKLY" || job.Schedules[0].Type.Valid() || job.Schedules[0].EveryType != "WEEK" {
+		t.Errorf("unexpected schedules %+v", job.Schedules)
+	}
+
+	// and sent back unchanged
+	b, err := json.Marshal(job.Schedules[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schedule map[string]interface{}
+	if err := json.Unmarshal(b, &schedule); err != nil {
+		t.Fatal(err)
+	}
+	if schedule["type"] != "WEEKLY" || schedule["everyType"] != "WEEK" {
+		t.Errorf("unexpected encoded schedule %s", b)
+	}
+}
@@ -0,0 +1,169 @@
+package nakivo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Kinds of errors. Every error returned for a failed call is an *Error which can be matched
+// against these with errors.Is.
+var (
+	ErrAuthenticationRequired = errors.New("authentication required")
+	ErrPermissionDenied       = errors.New("permission denied")
+	ErrNotFound               = errors.New("not found")
+	ErrLicenseViolation       = errors.New("license violation")
+	ErrLockout                = errors.New("locked out")
+	ErrServerError            = errors.New("server error")
+	ErrMalformedResponse      = errors.New("malformed response")
+)
+
+// Error describes a failed call. The error reported by the director is available as *APIError
+// through errors.As.
+type Error struct {
+	// Action of the failed request
+	Action string
+
+	// Method of the failed request
+	Method string
+
+	// Transaction id of the failed request
+	Tid int
+
+	// HTTP status code of the response. Zero if no response was received.
+	StatusCode int
+
+	// Kind of the error, one of the Err* variables. nil if the error could not be classified.
+	Kind error
+
+	// Err is the underlying error.
+	Err error
+}
+
+func (err *Error) Error() string {
+	var b strings.Builder
+	if err.Action != "" || err.Method != "" {
+		fmt.Fprintf(&b, "%s.%s", err.Action, err.Method)
+		if err.Tid != 0 {
+			fmt.Fprintf(&b, " (tid %d)", err.Tid)
+		}
+		b.WriteString(": ")
+	}
+	if err.Kind != nil {
+		fmt.Fprintf(&b, "%s: ", err.Kind)
+	}
+	if err.StatusCode >= http.StatusBadRequest {
+		fmt.Fprintf(&b, "http status %d: ", err.StatusCode)
+	}
+	b.WriteString(err.Err.Error())
+	return b.String()
+}
+
+func (err *Error) Unwrap() error {
+	return err.Err
+}
+
+// Is reports whether target is the kind of the error.
+func (err *Error) Is(target error) bool {
+	return err.Kind != nil && err.Kind == target
+}
+
+// errorHints are fragments of the message or cause the director reports, mapped to the kind of
+// error they indicate. The first matching kind wins.
+var errorHints = []struct {
+	kind  error
+	hints []string
+}{
+	{ErrLockout, []string{"locked out", "lockout", "too many failed", "too many login"}},
+	{ErrAuthenticationRequired, []string{"not logged", "not authenticated", "authentication required", "session expired", "session has expired"}},
+	{ErrPermissionDenied, []string{"permission", "access denied", "not permitted", "not allowed", "forbidden"}},
+	{ErrLicenseViolation, []string{"license", "licence"}},
+	{ErrNotFound, []string{"not found", "does not exist", "no such"}},
+}
+
+// wrapError attaches the context of a failed exchange to err and classifies it. request and
+// resp may be nil if unknown.
+func wrapError(err error, request *Request, resp *http.Response) error {
+	e, ok := err.(*Error)
+	if !ok {
+		e = &Error{Err: err}
+	}
+	if request != nil {
+		if e.Action == "" && e.Method == "" {
+			e.Action, e.Method = request.Action, request.Method
+		}
+		if e.Tid == 0 {
+			e.Tid = request.Tid
+		}
+	}
+	if resp != nil && e.StatusCode == 0 {
+		e.StatusCode = resp.StatusCode
+	}
+	if kind := classify(e); kind != nil {
+		e.Kind = kind
+	}
+	return e
+}
+
+// responseContext attaches action, method and tid of the decoded response to err.
+func responseContext(err error, response *Response) error {
+	e, ok := err.(*Error)
+	if !ok {
+		e = &Error{Err: err}
+	}
+	if e.Action == "" && e.Method == "" {
+		e.Action, e.Method = response.Action, response.Method
+	}
+	if e.Tid == 0 {
+		e.Tid, _ = strconv.Atoi(response.Tid)
+	}
+	return e
+}
+
+// Is reports whether target is the kind of error the message or cause indicate, so that errors
+// reported for single requests of a batch can be matched with errors.Is as well.
+func (err *APIError) Is(target error) bool {
+	kind := err.kind()
+	return kind != nil && kind == target
+}
+
+// kind determines the kind of the error from its message and cause, nil if unknown.
+func (err *APIError) kind() error {
+	s := strings.ToLower(err.Message + " " + err.Cause)
+	for _, h := range errorHints {
+		for _, hint := range h.hints {
+			if strings.Contains(s, hint) {
+				return h.kind
+			}
+		}
+	}
+	return nil
+}
+
+// classify determines the kind of e from the error reported by the director and the HTTP
+// status. nil keeps the current kind.
+func classify(e *Error) error {
+	var apiErr *APIError
+	if errors.As(e.Err, &apiErr) {
+		if kind := apiErr.kind(); kind != nil {
+			return kind
+		}
+	}
+	switch {
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrAuthenticationRequired
+	case e.StatusCode == http.StatusForbidden:
+		return ErrPermissionDenied
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode >= http.StatusInternalServerError:
+		return ErrServerError
+	}
+	return nil
+}
+
+func isNotLoggedIn(err error) bool {
+	return errors.Is(err, ErrAuthenticationRequired)
+}
@@ -0,0 +1,100 @@
+package nakivo_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/peertechde/go-nakivo"
+)
+
+func TestErrorKinds(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    *nakivo.APIError
+		status int
+		kind   error
+	}{
+		{name: "not logged in", err: &nakivo.APIError{Message: "Not logged in", Cause: "NotAuthenticatedException"}, kind: nakivo.ErrAuthenticationRequired},
+		{name: "permission", err: &nakivo.APIError{Message: "Permission denied", Cause: "AccessDeniedException"}, kind: nakivo.ErrPermissionDenied},
+		{name: "not found", err: &nakivo.APIError{Message: "Job 12 does not exist", Cause: "ItemNotFoundException"}, kind: nakivo.ErrNotFound},
+		{name: "license", err: &nakivo.APIError{Message: "The license has expired", Cause: "LicenseException"}, kind: nakivo.ErrLicenseViolation},
+		{name: "lockout", err: &nakivo.APIError{Message: "Too many failed login attempts", Cause: "LockoutException"}, kind: nakivo.ErrLockout},
+		{name: "unknown", err: &nakivo.APIError{Message: "Something went wrong", Cause: "RuntimeException"}},
+		{name: "unauthorized", status: http.StatusUnauthorized, kind: nakivo.ErrAuthenticationRequired},
+		{name: "forbidden", status: http.StatusForbidden, kind: nakivo.ErrPermissionDenied},
+		{name: "http not found", status: http.StatusNotFound, kind: nakivo.ErrNotFound},
+		{name: "server error", status: http.StatusInternalServerError, kind: nakivo.ErrServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := router(func(request *nakivo.Request) *nakivo.Response {
+				return &nakivo.Response{Message: tt.err.Message, Cause: tt.err.Cause}
+			})
+			if tt.err == nil {
+				handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					http.Error(w, http.StatusText(tt.status), tt.status)
+				})
+			}
+			client := testClient(t, handler)
+			_, _, err := client.Job.JobInfo(context.Background(), []int{10}, 0)
+
+			var e *nakivo.Error
+			if !errors.As(err, &e) {
+				t.Fatalf("expected *Error, got %T (%v)", err, err)
+			}
+			if e.Action != nakivo.JobAction || e.Method != "getJobInfo" || e.Tid == 0 {
+				t.Errorf("unexpected context of %v", e)
+			}
+			if tt.kind == nil {
+				if e.Kind != nil {
+					t.Errorf("expected no kind, got %v", e.Kind)
+				}
+			} else if !errors.Is(err, tt.kind) {
+				t.Errorf("expected %v, got %v", tt.kind, err)
+			}
+
+			var apiErr *nakivo.APIError
+			if tt.err != nil && (!errors.As(err, &apiErr) || apiErr.Cause != tt.err.Cause) {
+				t.Errorf("expected the *APIError reported by the director, got %v", err)
+			}
+			if tt.status != 0 && e.StatusCode != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, e.StatusCode)
+			}
+		})
+	}
+}
+
+func TestBatchErrorKinds(t *testing.T) {
+	client := testClient(t, router(func(request *nakivo.Request) *nakivo.Response {
+		if request.Method == "ignore" {
+			return nil
+		}
+		return &nakivo.Response{Message: "Permission denied", Cause: "AccessDeniedException"}
+	}))
+	batch := client.NewBatch()
+	denied := batch.Add("Test", "deny", nil, nil)
+	unanswered := batch.Add("Test", "ignore", nil, nil)
+	if _, err := batch.Do(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var e *nakivo.Error
+	if !errors.Is(denied.Err, nakivo.ErrPermissionDenied) || !errors.As(denied.Err, &e) || e.Method != "deny" || e.Tid != denied.Request.Tid {
+		t.Errorf("expected ErrPermissionDenied of the call, got %v", denied.Err)
+	}
+	if !errors.Is(unanswered.Err, nakivo.ErrMalformedResponse) {
+		t.Errorf("expected ErrMalformedResponse for the unanswered call, got %v", unanswered.Err)
+	}
+}
+
+func TestAPIErrorIs(t *testing.T) {
+	err := &nakivo.APIError{Message: "Job group 7 not found", Cause: "ItemNotFoundException"}
+	if !errors.Is(err, nakivo.ErrNotFound) {
+		t.Errorf("expected %v to be ErrNotFound", err)
+	}
+	if errors.Is(err, nakivo.ErrPermissionDenied) {
+		t.Errorf("expected %v not to be ErrPermissionDenied", err)
+	}
+}
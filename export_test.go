@@ -0,0 +1,4 @@
+package nakivo
+
+// DiffJobs exports diffJobs to the tests.
+var DiffJobs = diffJobs
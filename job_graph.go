@@ -0,0 +1,267 @@
+package nakivo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TriggerGraph is the graph of jobs triggering each other by TRIGGER schedules.
+type TriggerGraph struct {
+	// Nodes of the jobs, in the order of the jobs the graph was built from
+	Nodes []*TriggerNode
+
+	// Edges from triggering to triggered jobs
+	Edges []*TriggerEdge
+
+	nodes map[string]*TriggerNode
+}
+
+// TriggerNode is a job in the trigger graph.
+type TriggerNode struct {
+	Job *Job
+
+	// Triggers are the edges to the jobs triggered by this job
+	Triggers []*TriggerEdge
+
+	// TriggeredBy are the edges from the jobs triggering this job
+	TriggeredBy []*TriggerEdge
+}
+
+// TriggerEdge is a TRIGGER schedule of a job.
+type TriggerEdge struct {
+	// From is the triggering job, nil if the job is unknown or removed
+	From *TriggerNode
+
+	// To is the triggered job
+	To *TriggerNode
+
+	// Schedule of the triggered job
+	Schedule *Schedule
+
+	// Vid and name of the triggering job as referenced by the schedule
+	FromVid  string
+	FromName string
+
+	// Run outcomes of the triggering job which trigger the job
+	Events []TriggerEvent
+}
+
+// Dangling reports whether the edge references a job which is unknown or removed.
+func (e *TriggerEdge) Dangling() bool {
+	return e.From == nil
+}
+
+// NewTriggerGraph builds the trigger graph of jobs, e.g. of the result of JobService.JobInfo.
+// Removed jobs are not part of the graph, so triggers referencing them are dangling.
+func NewTriggerGraph(jobs []Job) *TriggerGraph {
+	g := &TriggerGraph{nodes: make(map[string]*TriggerNode)}
+	for i := range jobs {
+		if jobs[i].IsRemoved {
+			continue
+		}
+		node := &TriggerNode{Job: &jobs[i]}
+		g.Nodes = append(g.Nodes, node)
+		g.nodes[jobs[i].Vid] = node
+	}
+	for _, node := range g.Nodes {
+		for i := range node.Job.Schedules {
+			schedule := &node.Job.Schedules[i]
+			if schedule.Type != ScheduleTypeTrigger {
+				continue
+			}
+			edge := &TriggerEdge{
+				From:     g.nodes[schedule.TriggerItem],
+				To:       node,
+				Schedule: schedule,
+				FromVid:  schedule.TriggerItem,
+				FromName: schedule.TriggerItemName,
+				Events:   schedule.TriggerEvents,
+			}
+			g.Edges = append(g.Edges, edge)
+			node.TriggeredBy = append(node.TriggeredBy, edge)
+			if edge.From != nil {
+				edge.From.Triggers = append(edge.From.Triggers, edge)
+			}
+		}
+	}
+	return g
+}
+
+// Node returns the node of the job with vid, nil if the job is not part of the graph.
+func (g *TriggerGraph) Node(vid string) *TriggerNode {
+	return g.nodes[vid]
+}
+
+// Dangling returns the edges referencing jobs which are unknown or removed.
+func (g *TriggerGraph) Dangling() []*TriggerEdge {
+	var dangling []*TriggerEdge
+	for _, edge := range g.Edges {
+		if edge.Dangling() {
+			dangling = append(dangling, edge)
+		}
+	}
+	return dangling
+}
+
+// Cycles returns the groups of jobs triggering each other in a cycle, including jobs which
+// trigger themselves. Jobs of a cycle are ordered as in the graph.
+func (g *TriggerGraph) Cycles() [][]*TriggerNode {
+	// Tarjan's strongly connected components
+	var (
+		index   = make(map[*TriggerNode]int)
+		lowlink = make(map[*TriggerNode]int)
+		onStack = make(map[*TriggerNode]bool)
+		stack   []*TriggerNode
+		cycles  [][]*TriggerNode
+		visit   func(node *TriggerNode)
+	)
+	visit = func(node *TriggerNode) {
+		index[node] = len(index)
+		lowlink[node] = index[node]
+		stack = append(stack, node)
+		onStack[node] = true
+
+		selfLoop := false
+		for _, edge := range node.Triggers {
+			next := edge.To
+			if next == node {
+				selfLoop = true
+			}
+			if _, ok := index[next]; !ok {
+				visit(next)
+				lowlink[node] = min(lowlink[node], lowlink[next])
+			} else if onStack[next] {
+				lowlink[node] = min(lowlink[node], index[next])
+			}
+		}
+
+		if lowlink[node] != index[node] {
+			return
+		}
+		var component []*TriggerNode
+		for {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[n] = false
+			component = append(component, n)
+			if n == node {
+				break
+			}
+		}
+		if len(component) > 1 || selfLoop {
+			cycles = append(cycles, g.ordered(component))
+		}
+	}
+	for _, node := range g.Nodes {
+		if _, ok := index[node]; !ok {
+			visit(node)
+		}
+	}
+	return cycles
+}
+
+// ordered returns nodes in the order of the graph.
+func (g *TriggerGraph) ordered(nodes []*TriggerNode) []*TriggerNode {
+	in := make(map[*TriggerNode]bool, len(nodes))
+	for _, node := range nodes {
+		in[node] = true
+	}
+	ordered := make([]*TriggerNode, 0, len(nodes))
+	for _, node := range g.Nodes {
+		if in[node] {
+			ordered = append(ordered, node)
+		}
+	}
+	return ordered
+}
+
+// DOT returns the graph in the Graphviz DOT language. Dangling triggers are drawn from dashed
+// nodes, disabled schedules as dashed edges.
+func (g *TriggerGraph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph triggers {\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&b, "\t%s [label=%s];\n", dotQuote(node.Job.Vid), dotQuote(node.Job.Name))
+	}
+	for _, vid := range g.danglingVids() {
+		fmt.Fprintf(&b, "\t%s [label=%s, style=dashed];\n", dotQuote(vid.vid), dotQuote(vid.name+" (missing)"))
+	}
+	for _, edge := range g.Edges {
+		attrs := []string{"label=" + dotQuote(eventsLabel(edge.Events))}
+		if !edge.Schedule.Enabled {
+			attrs = append(attrs, "style=dashed")
+		}
+		fmt.Fprintf(&b, "\t%s -> %s [%s];\n", dotQuote(edge.FromVid), dotQuote(edge.To.Job.Vid), strings.Join(attrs, ", "))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid returns the graph as a Mermaid flowchart. Dangling triggers are drawn from dashed
+// nodes, disabled schedules as dotted edges.
+func (g *TriggerGraph) Mermaid() string {
+	ids := make(map[string]string)
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, node := range g.Nodes {
+		id := fmt.Sprintf("n%d", i)
+		ids[node.Job.Vid] = id
+		fmt.Fprintf(&b, "\t%s[%s]\n", id, mermaidQuote(node.Job.Name))
+	}
+	for i, vid := range g.danglingVids() {
+		id := fmt.Sprintf("m%d", i)
+		ids[vid.vid] = id
+		fmt.Fprintf(&b, "\t%s[%s]\n", id, mermaidQuote(vid.name+" (missing)"))
+		fmt.Fprintf(&b, "\tstyle %s stroke-dasharray: 5 5\n", id)
+	}
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		if !edge.Schedule.Enabled {
+			arrow = "-.->"
+		}
+		label := ""
+		if len(edge.Events) > 0 {
+			label = "|" + mermaidQuote(eventsLabel(edge.Events)) + "|"
+		}
+		fmt.Fprintf(&b, "\t%s %s%s %s\n", ids[edge.FromVid], arrow, label, ids[edge.To.Job.Vid])
+	}
+	return b.String()
+}
+
+type danglingVid struct {
+	vid, name string
+}
+
+// danglingVids returns the vids referenced by dangling edges, in the order of the edges.
+func (g *TriggerGraph) danglingVids() []danglingVid {
+	seen := make(map[string]bool)
+	var vids []danglingVid
+	for _, edge := range g.Dangling() {
+		if seen[edge.FromVid] {
+			continue
+		}
+		seen[edge.FromVid] = true
+		name := edge.FromName
+		if name == "" {
+			name = edge.FromVid
+		}
+		vids = append(vids, danglingVid{vid: edge.FromVid, name: name})
+	}
+	return vids
+}
+
+func eventsLabel(events []TriggerEvent) string {
+	labels := make([]string, len(events))
+	for i, event := range events {
+		labels[i] = string(event)
+	}
+	return strings.Join(labels, ", ")
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(s) + `"`
+}
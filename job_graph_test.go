@@ -0,0 +1,136 @@
+package nakivo_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/peertechde/go-nakivo"
+)
+
+// trigger returns a TRIGGER schedule of the job with vid and name.
+func trigger(t *testing.T, vid, name string, events ...nakivo.TriggerEvent) nakivo.Schedule {
+	t.Helper()
+	schedule := build(t, nakivo.NewSchedule().TriggeredBy(vid, events...))
+	schedule.TriggerItemName = name
+	return schedule
+}
+
+func names(nodes []*nakivo.TriggerNode) []string {
+	var names []string
+	for _, node := range nodes {
+		names = append(names, node.Job.Name)
+	}
+	return names
+}
+
+func TestTriggerGraph(t *testing.T) {
+	jobs := []nakivo.Job{
+		{Vid: "a", Name: "A", Schedules: []nakivo.Schedule{build(t, nakivo.NewSchedule().Daily().At(22, 0))}},
+		{Vid: "b", Name: "B", Schedules: []nakivo.Schedule{trigger(t, "a", "A")}},
+		{Vid: "c", Name: "C", Schedules: []nakivo.Schedule{trigger(t, "a", "A"), trigger(t, "b", "B")}},
+	}
+	g := nakivo.NewTriggerGraph(jobs)
+	if got := names(g.Nodes); !reflect.DeepEqual(got, []string{"A", "B", "C"}) {
+		t.Fatalf("unexpected nodes %v", got)
+	}
+	if len(g.Edges) != 3 || len(g.Dangling()) != 0 || len(g.Cycles()) != 0 {
+		t.Fatalf("expected 3 edges without dangling ones and cycles, got %+v", g.Edges)
+	}
+
+	a, c := g.Node("a"), g.Node("c")
+	if len(a.Triggers) != 2 || a.Triggers[0].To != g.Node("b") || a.Triggers[1].To != c {
+		t.Errorf("expected A to trigger B and C, got %+v", a.Triggers)
+	}
+	if len(c.TriggeredBy) != 2 || c.TriggeredBy[0].From != a || c.TriggeredBy[1].From != g.Node("b") {
+		t.Errorf("expected C to be triggered by A and B, got %+v", c.TriggeredBy)
+	}
+	if edge := c.TriggeredBy[0]; edge.Schedule != &c.Job.Schedules[0] || !reflect.DeepEqual(edge.Events, []nakivo.TriggerEvent{nakivo.TriggerEventRunSuccess}) {
+		t.Errorf("unexpected edge %+v", edge)
+	}
+}
+
+func TestTriggerGraphCycles(t *testing.T) {
+	jobs := []nakivo.Job{
+		{Vid: "a", Name: "A", Schedules: []nakivo.Schedule{trigger(t, "c", "C")}},
+		{Vid: "self", Name: "Self", Schedules: []nakivo.Schedule{trigger(t, "self", "Self")}},
+		{Vid: "b", Name: "B", Schedules: []nakivo.Schedule{trigger(t, "a", "A")}},
+		{Vid: "c", Name: "C", Schedules: []nakivo.Schedule{trigger(t, "b", "B")}},
+		// triggered by a cycle, but not part of it
+		{Vid: "d", Name: "D", Schedules: []nakivo.Schedule{trigger(t, "c", "C")}},
+	}
+	var got [][]string
+	for _, cycle := range nakivo.NewTriggerGraph(jobs).Cycles() {
+		got = append(got, names(cycle))
+	}
+	want := [][]string{{"A", "B", "C"}, {"Self"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected cycles %v, got %v", want, got)
+	}
+}
+
+func TestTriggerGraphDangling(t *testing.T) {
+	jobs := []nakivo.Job{
+		{Vid: "removed", Name: "Removed", IsRemoved: true},
+		{Vid: "a", Name: "A", Schedules: []nakivo.Schedule{trigger(t, "removed", "Removed"), trigger(t, "unknown", "")}},
+	}
+	g := nakivo.NewTriggerGraph(jobs)
+	if g.Node("removed") != nil || len(g.Nodes) != 1 {
+		t.Errorf("expected the removed job not to be part of the graph, got %v", names(g.Nodes))
+	}
+	dangling := g.Dangling()
+	if len(dangling) != 2 || dangling[0].FromVid != "removed" || dangling[1].FromVid != "unknown" {
+		t.Fatalf("expected the triggers of removed and unknown jobs to dangle, got %+v", dangling)
+	}
+	for _, edge := range dangling {
+		if !edge.Dangling() || edge.From != nil || edge.To != g.Node("a") {
+			t.Errorf("unexpected dangling edge %+v", edge)
+		}
+	}
+	if len(g.Node("a").TriggeredBy) != 2 {
+		t.Errorf("expected dangling edges to trigger A, got %+v", g.Node("a").TriggeredBy)
+	}
+}
+
+func TestTriggerGraphRender(t *testing.T) {
+	disabled := trigger(t, "b", "B")
+	disabled.Enabled = false
+	jobs := []nakivo.Job{
+		{Vid: "a", Name: `Backup "A"`},
+		{Vid: "b", Name: "B", Schedules: []nakivo.Schedule{trigger(t, "a", "A", nakivo.TriggerEventRunSuccess, nakivo.TriggerEventRunFailure)}},
+		{Vid: "c", Name: "C", Schedules: []nakivo.Schedule{disabled, trigger(t, "gone", "Old job")}},
+		{Vid: "d", Name: "D", Schedules: []nakivo.Schedule{trigger(t, "gone", "Old job")}},
+	}
+	g := nakivo.NewTriggerGraph(jobs)
+
+	wantDOT := `digraph triggers {
+	"a" [label="Backup \"A\""];
+	"b" [label="B"];
+	"c" [label="C"];
+	"d" [label="D"];
+	"gone" [label="Old job (missing)", style=dashed];
+	"a" -> "b" [label="RUN_SUCCESS, RUN_FAILURE"];
+	"b" -> "c" [label="RUN_SUCCESS", style=dashed];
+	"gone" -> "c" [label="RUN_SUCCESS"];
+	"gone" -> "d" [label="RUN_SUCCESS"];
+}
+`
+	if got := g.DOT(); got != wantDOT {
+		t.Errorf("expected DOT\n%s\ngot\n%s", wantDOT, got)
+	}
+
+	wantMermaid := `flowchart LR
+	n0["Backup #quot;A#quot;"]
+	n1["B"]
+	n2["C"]
+	n3["D"]
+	m0["Old job (missing)"]
+	style m0 stroke-dasharray: 5 5
+	n0 -->|"RUN_SUCCESS, RUN_FAILURE"| n1
+	n1 -.->|"RUN_SUCCESS"| n2
+	m0 -->|"RUN_SUCCESS"| n2
+	m0 -->|"RUN_SUCCESS"| n3
+`
+	if got := g.Mermaid(); got != wantMermaid {
+		t.Errorf("expected Mermaid\n%s\ngot\n%s", wantMermaid, got)
+	}
+}
@@ -162,28 +162,46 @@ type Storage struct {
 	Type string `json:"type"`
 }
 
-// List lists all jobs in a group
-//
-// TODO: Currently limited to list ALL jobs in a group. To list a limited set of group, the first
-// element of the data element in the request should relflect a group id.
+// List lists all job groups as a flat slice. Use ListGroups to list selected groups as a tree.
 func (s *JobService) List(ctx context.Context, clientTimeOffset int, collectAllChildJobs bool) (*Groups, *http.Response, error) {
-	request := Request{
-		Action: JobAction,
-		Method: "getGroupInfo",
-		Data:   []interface{}{[]interface{}{nil}, clientTimeOffset, collectAllChildJobs},
-		Type:   "rpc",
-		Tid:    1,
-	}
+	return s.listGroups(ctx, nil, clientTimeOffset, collectAllChildJobs)
+}
 
-	req, err := s.client.NewRequest(&request)
-	if err != nil {
-		return nil, nil, err
+// ListOptions selects the job groups listed by ListGroups.
+type ListOptions struct {
+	// Ids of the groups to list. No ids select the root group and thereby all groups.
+	GroupIds []int
+
+	// Offset of the time zone of the client in ms, applied to the dates of the result
+	ClientTimeOffset int
+
+	// CollectAllChildJobs collects the ids of the jobs of all sub groups into ChildJobIds
+	// instead of only the jobs immediately inside a group.
+	CollectAllChildJobs bool
+}
+
+// ListGroups lists the job groups selected by opts as a tree. A nil opts lists the root group.
+func (s *JobService) ListGroups(ctx context.Context, opts *ListOptions) (*GroupTree, *http.Response, error) {
+	if opts == nil {
+		opts = &ListOptions{}
 	}
-	var groups Groups
-	r := Response{Data: &groups}
-	resp, err := s.client.Do(ctx, req, &r)
+	groups, resp, err := s.listGroups(ctx, opts.GroupIds, opts.ClientTimeOffset, opts.CollectAllChildJobs)
 	if err != nil {
 		return nil, resp, err
 	}
-	return &groups, resp, nil
+	tree := groups.Tree()
+	tree.Bind(s, opts.ClientTimeOffset)
+	return tree, resp, nil
+}
+
+func (s *JobService) listGroups(ctx context.Context, ids []int, clientTimeOffset int, collectAllChildJobs bool) (*Groups, *http.Response, error) {
+	// the director expects [null] to select the root group
+	selector := []interface{}{nil}
+	if len(ids) > 0 {
+		selector = make([]interface{}, 0, len(ids))
+		for _, id := range ids {
+			selector = append(selector, id)
+		}
+	}
+	return Invoke[Groups](ctx, s.client, JobAction, "getGroupInfo", selector, clientTimeOffset, collectAllChildJobs)
 }
@@ -0,0 +1,43 @@
+package nakivo_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestList(t *testing.T) {
+	client := fixtureClient(t, "list")
+	ctx := context.Background()
+
+	if _, _, err := client.Authentication.Login(ctx, "admin", "secret", false); err != nil {
+		t.Fatal(err)
+	}
+	groups, _, err := client.Job.List(ctx, 0, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups.Children) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(groups.Children))
+	}
+
+	production, databases := groups.Children[0], groups.Children[1]
+	if production.Name != "Production" || databases.Name != "Databases" {
+		t.Fatalf("unexpected groups %q and %q", production.Name, databases.Name)
+	}
+	if want := []int{2, 10}; !reflect.DeepEqual(production.ImmediateChildJobIds, want) {
+		t.Errorf("expected immediate children %v, got %v", want, production.ImmediateChildJobIds)
+	}
+	if want := []int{10, 11}; !reflect.DeepEqual(production.ChildJobIds, want) {
+		t.Errorf("expected child jobs %v, got %v", want, production.ChildJobIds)
+	}
+	if production.JobCount.Backup != 1 || production.JobCount.Replication != 1 {
+		t.Errorf("unexpected job count %+v", production.JobCount)
+	}
+	if production.VMCount != 4 || production.LrJobOk != 1 || !production.HasLastRun {
+		t.Errorf("unexpected totals of %+v", production)
+	}
+	if databases.JobCountEnabled != 1 || databases.JobCount.Replication != 1 {
+		t.Errorf("unexpected totals of %+v", databases)
+	}
+}
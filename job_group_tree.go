@@ -0,0 +1,243 @@
+package nakivo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// SkipChildren may be returned by a WalkFunc to skip the sub groups of the visited group.
+var SkipChildren = errors.New("skip children")
+
+// WalkFunc is called for every group visited by Walk, with the depth of the group below the
+// node the walk started at.
+type WalkFunc func(node *GroupNode, depth int) error
+
+// GroupTree is the hierarchy of the job groups returned by getGroupInfo.
+type GroupTree struct {
+	// Groups which are not a child of any other group of the tree
+	Roots []*GroupNode
+
+	nodes map[int]*GroupNode
+
+	// service and clientTimeOffset are used to resolve job ids to jobs
+	service          *JobService
+	clientTimeOffset int
+
+	mu   sync.Mutex
+	jobs map[int]*Job
+}
+
+// GroupNode is a job group within a GroupTree.
+type GroupNode struct {
+	Group
+
+	// Parent group, nil for the roots of the tree
+	Parent *GroupNode
+
+	// Sub groups of the group
+	Children []*GroupNode
+
+	// Ids of the jobs immediately inside the group
+	JobIds []int
+
+	// Ids of the immediate children which are neither jobs of the group nor groups of the tree,
+	// e.g. of sub groups which were not part of the result of getGroupInfo
+	UnresolvedIds []int
+}
+
+// Tree links the groups to a tree by their immediate child ids. Immediate children which are
+// listed in ChildJobIds are the jobs of a group. The order of the groups and their children is
+// kept.
+func (g *Groups) Tree() *GroupTree {
+	tree := &GroupTree{nodes: make(map[int]*GroupNode, len(g.Children))}
+	nodes := make([]*GroupNode, 0, len(g.Children))
+	for _, group := range g.Children {
+		node := &GroupNode{Group: group}
+		tree.nodes[group.Id] = node
+		nodes = append(nodes, node)
+	}
+	for _, node := range nodes {
+		jobs := make(map[int]bool, len(node.ChildJobIds))
+		for _, id := range node.ChildJobIds {
+			jobs[id] = true
+		}
+		for _, id := range node.ImmediateChildJobIds {
+			child, ok := tree.nodes[id]
+			switch {
+			case ok && child.Parent == nil && !child.isAncestorOf(node):
+				child.Parent = node
+				node.Children = append(node.Children, child)
+			case jobs[id]:
+				node.JobIds = append(node.JobIds, id)
+			default:
+				node.UnresolvedIds = append(node.UnresolvedIds, id)
+			}
+		}
+	}
+	for _, node := range nodes {
+		if node.Parent == nil {
+			tree.Roots = append(tree.Roots, node)
+		}
+	}
+	return tree
+}
+
+// Group returns the group with id, nil if it's not part of the tree.
+func (t *GroupTree) Group(id int) *GroupNode {
+	return t.nodes[id]
+}
+
+// Bind binds the tree to the job service used to resolve the ids of the jobs of its groups.
+// Trees returned by ListGroups are already bound.
+func (t *GroupTree) Bind(s *JobService, clientTimeOffset int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.service = s
+	t.clientTimeOffset = clientTimeOffset
+}
+
+// Len returns the number of groups in the tree.
+func (t *GroupTree) Len() int {
+	return len(t.nodes)
+}
+
+// isAncestorOf reports whether n is node itself or one of its ancestors.
+func (n *GroupNode) isAncestorOf(node *GroupNode) bool {
+	for ; node != nil; node = node.Parent {
+		if node == n {
+			return true
+		}
+	}
+	return false
+}
+
+// Walk visits all groups of the tree depth first, parents before their children. If fn returns
+// SkipChildren, the sub groups of the group are skipped; any other error stops the walk and is
+// returned.
+func (t *GroupTree) Walk(fn WalkFunc) error {
+	for _, root := range t.Roots {
+		if err := root.Walk(fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Walk visits the group and all its sub groups depth first, see GroupTree.Walk.
+func (n *GroupNode) Walk(fn WalkFunc) error {
+	err := n.walk(fn, 0)
+	if err == SkipChildren {
+		return nil
+	}
+	return err
+}
+
+func (n *GroupNode) walk(fn WalkFunc, depth int) error {
+	if err := fn(n, depth); err != nil {
+		return err
+	}
+	for _, child := range n.Children {
+		if err := child.walk(fn, depth+1); err != nil && err != SkipChildren {
+			return err
+		}
+	}
+	return nil
+}
+
+// Find returns the group at path, the names of the groups from a root down to the group
+// separated by slashes, e.g. "Production/SQL". nil if there's no such group.
+func (t *GroupTree) Find(path string) *GroupNode {
+	names := strings.Split(strings.Trim(path, "/"), "/")
+	candidates := t.Roots
+	var node *GroupNode
+	for _, name := range names {
+		node = nil
+		for _, candidate := range candidates {
+			if candidate.Name == name {
+				node = candidate
+				break
+			}
+		}
+		if node == nil {
+			return nil
+		}
+		candidates = node.Children
+	}
+	return node
+}
+
+// Path returns the names of the groups from the root of the tree down to the group, separated
+// by slashes.
+func (n *GroupNode) Path() string {
+	var names []string
+	for node := n; node != nil; node = node.Parent {
+		names = append(names, node.Name)
+	}
+	for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+		names[i], names[j] = names[j], names[i]
+	}
+	return strings.Join(names, "/")
+}
+
+// Jobs resolves the ids of the jobs immediately inside node to jobs. Jobs are fetched with
+// JobInfo on first use and cached by the tree.
+func (t *GroupTree) Jobs(ctx context.Context, node *GroupNode) ([]Job, error) {
+	return t.resolve(ctx, node.JobIds)
+}
+
+// AllJobs resolves the ids of the jobs inside node and all its sub groups to jobs, see Jobs.
+// Jobs of sub groups which are not part of the tree are not included.
+func (t *GroupTree) AllJobs(ctx context.Context, node *GroupNode) ([]Job, error) {
+	var ids []int
+	node.Walk(func(n *GroupNode, depth int) error {
+		ids = append(ids, n.JobIds...)
+		return nil
+	})
+	return t.resolve(ctx, ids)
+}
+
+// resolve returns the jobs with ids, fetching the ones not cached yet in a single call. Ids
+// unknown to the director, e.g. of removed jobs, are skipped.
+func (t *GroupTree) resolve(ctx context.Context, ids []int) ([]Job, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.jobs == nil {
+		t.jobs = make(map[int]*Job)
+	}
+	var missing []int
+	for _, id := range ids {
+		if _, ok := t.jobs[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		if t.service == nil {
+			return nil, fmt.Errorf("group tree is not bound to a job service")
+		}
+		jobs, _, err := t.service.JobInfo(ctx, missing, t.clientTimeOffset)
+		if err != nil {
+			return nil, err
+		}
+		for i := range jobs.Children {
+			t.jobs[jobs.Children[i].Id] = &jobs.Children[i]
+		}
+		// remember unknown ids to not ask for them again
+		for _, id := range missing {
+			if _, ok := t.jobs[id]; !ok {
+				t.jobs[id] = nil
+			}
+		}
+	}
+
+	result := make([]Job, 0, len(ids))
+	for _, id := range ids {
+		if job := t.jobs[id]; job != nil {
+			result = append(result, *job)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,97 @@
+package nakivo_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/peertechde/go-nakivo"
+)
+
+// recordJobInfo returns middleware collecting the ids requested with getJobInfo into ids.
+func recordJobInfo(ids *[]int) nakivo.Middleware {
+	return func(next nakivo.Handler) nakivo.Handler {
+		return func(ctx context.Context, ex *nakivo.Exchange) error {
+			for _, request := range ex.Requests {
+				if request.Method != "getJobInfo" {
+					continue
+				}
+				params := requestParams(request)
+				var requested []int
+				json.Unmarshal(params[0], &requested)
+				*ids = append(*ids, requested...)
+			}
+			return next(ctx, ex)
+		}
+	}
+}
+
+func TestListGroupsTree(t *testing.T) {
+	srv := newDirector()
+	defer srv.Close()
+
+	var requested []int
+	client := loggedIn(t, srv, nakivo.WithMiddleware(recordJobInfo(&requested)))
+	ctx := context.Background()
+
+	tree, _, err := client.Job.ListGroups(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	production := tree.Find("Production")
+	databases := tree.Find("Production/Databases")
+	if production == nil || databases == nil || databases.Parent != production {
+		t.Fatalf("unexpected tree %+v", tree.Roots)
+	}
+	if !reflect.DeepEqual(production.JobIds, []int{10}) || len(production.UnresolvedIds) != 0 {
+		t.Errorf("expected job 10 in Production, got %v and unresolved %v", production.JobIds, production.UnresolvedIds)
+	}
+
+	jobs, err := tree.AllJobs(ctx, production)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 || jobs[0].Id != 10 || jobs[1].Id != 11 {
+		t.Errorf("expected jobs 10 and 11, got %+v", jobs)
+	}
+	if !reflect.DeepEqual(requested, []int{10, 11}) {
+		t.Errorf("expected getJobInfo for jobs 10 and 11, got %v", requested)
+	}
+}
+
+func TestListGroupsUnresolvedSubGroups(t *testing.T) {
+	srv := newDirector()
+	defer srv.Close()
+
+	var requested []int
+	client := loggedIn(t, srv, nakivo.WithMiddleware(recordJobInfo(&requested)))
+	ctx := context.Background()
+
+	tree, _, err := client.Job.ListGroups(ctx, &nakivo.ListOptions{GroupIds: []int{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	production := tree.Group(1)
+	if production == nil || len(production.Children) != 0 {
+		t.Fatalf("expected Production without sub groups, got %+v", production)
+	}
+	// the sub group Databases was not listed, so its id is not taken as a job
+	if !reflect.DeepEqual(production.JobIds, []int{10}) {
+		t.Errorf("expected job 10, got %v", production.JobIds)
+	}
+	if !reflect.DeepEqual(production.UnresolvedIds, []int{2}) {
+		t.Errorf("expected the unresolved sub group 2, got %v", production.UnresolvedIds)
+	}
+
+	jobs, err := tree.AllJobs(ctx, production)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 || jobs[0].Id != 10 {
+		t.Errorf("expected job 10, got %+v", jobs)
+	}
+	if !reflect.DeepEqual(requested, []int{10}) {
+		t.Errorf("expected getJobInfo for job 10 only, got %v", requested)
+	}
+}
@@ -30,18 +30,18 @@ type Job struct {
 
 	// Site recovery specific: the type of recovery running for the last job run.
 	// Possible values: TEST, RUN
-	LrSiteRecoveryRunType string `json:"lrSiteRecoveryRunType"`
+	LrSiteRecoveryRunType SiteRecoveryRunType `json:"lrSiteRecoveryRunType"`
 
 	// Site recovery specific: the type of recovery time objective for the last job run.
 	// Possible values: MINUTE, HOUR
-	LrRecoveryTimeObjectiveType string `json:"lrRecoveryTimeObjectiveType"`
+	LrRecoveryTimeObjectiveType RecoveryTimeObjectiveType `json:"lrRecoveryTimeObjectiveType"`
 
 	// Site recovery specific: the recovery time objective for the last job run
 	LrRecoveryTimeObjective int `json:"lrRecoveryTimeObjective"`
 
 	// Site recovery specific: the failover type for the last job run.
 	// Possible values: PLANNED_FAILOVER, EMERGENCY_FAILOVER
-	LrFailoverType string `json:"lrFailoverType"`
+	LrFailoverType FailoverType `json:"lrFailoverType"`
 
 	// Site recovery specific: action failed for the last job run
 	LrActionFailed int `json:"lrActionFailed"`
@@ -63,19 +63,19 @@ type Job struct {
 
 	// Truncation mode of Microsoft SQL Server logging.
 	// Possible values: NONE, ALWAYS, JOB_SUCCESS
-	SqlLogTruncationMode string `json:"sqlLogTruncationMode"`
+	SqlLogTruncationMode LogTruncationMode `json:"sqlLogTruncationMode"`
 
 	// Type of full backup job run settings.
 	// Possible values: ALWAYS, EVERY, EVERY2ND, FIRST, SECOND, THIRD, FOURTH, LAST, DAY, EVERY_JOB_RUNS
-	FullBackupRunSettingsType string `json:"fullBackupRunSettingsType"`
+	FullBackupRunSettingsType FullBackupRunSettingsType `json:"fullBackupRunSettingsType"`
 
 	// Full backup mode.
 	// Possible values: SYNTHETIC, ACTIVE
-	FullBackupMode string `json:"fullBackupMode"`
+	FullBackupMode FullBackupMode `json:"fullBackupMode"`
 
 	// Type of recovery time objective.
 	// Possible values: MINUTE, HOUR
-	RecoveryTimeObjectiveType string `json:"recoveryTimeObjectiveType"`
+	RecoveryTimeObjectiveType RecoveryTimeObjectiveType `json:"recoveryTimeObjectiveType"`
 
 	// Recovery time objective
 	RecoveryTimeObjective int `json:"recoveryTimeObjective"`
@@ -83,19 +83,19 @@ type Job struct {
 	// Specific for site recovery job: the type of site recovery running for the current job
 	// run.
 	// Possible values: TEST, RUN
-	CrSiteRecoveryRunType string `json:"crSiteRecoveryRunType"`
+	CrSiteRecoveryRunType SiteRecoveryRunType `json:"crSiteRecoveryRunType"`
 
 	// Specific for site recovery job: the type of recovery time objective for the current job
 	// run.
 	// Possible values: MINUTE, HOUR
-	CrRecoveryTimeObjectiveType string `json:"crRecoveryTimeObjectiveType"`
+	CrRecoveryTimeObjectiveType RecoveryTimeObjectiveType `json:"crRecoveryTimeObjectiveType"`
 
 	// Specific for site recovery job: the recovery time objective for the current job run
 	CrRecoveryTimeObjective int `json:"crRecoveryTimeObjective"`
 
 	// Specific for site recovery job: the failover type for the current job run.
 	// Possible values: PLANNED_FAILOVER, EMERGENCY_FAILOVER
-	CrFailoverType string `json:"crFailoverType"`
+	CrFailoverType FailoverType `json:"crFailoverType"`
 
 	// List of actions
 	//
@@ -118,7 +118,7 @@ type Job struct {
 	// Job type.
 	// Possible values: REPLICATION, BACKUP, RECOVERY_VMS, RECOVERY_FILES, RECOVERY_OBJECTS,
 	// BACKUP_COPY, FLASH_BOOT, REPLICA_FAILOVER, SITE_RECOVERY
-	JobType string `json:"jobType"`
+	JobType JobType `json:"jobType"`
 
 	// Date the job got added
 	Added string `json:"added"`
@@ -158,7 +158,7 @@ type Job struct {
 
 	// Current state.
 	// Possible values: WAITING_DEMAND, WAITING_SCHEDULE, RUNNING, OK, FAILED, STOPPED
-	CrState string `json:"crState"`
+	CrState JobState `json:"crState"`
 
 	// Timestamp of the current job run
 	CrDate string `json:"crDate"`
@@ -189,7 +189,7 @@ type Job struct {
 
 	// The state of the last job run.
 	// ossible values: WAITING_DEMAND, WAITING_SCHEDULE, RUNNING, OK, FAILED, STOPPED
-	LrState string `json:"lrState"`
+	LrState JobState `json:"lrState"`
 
 	// The date and time of the last job run start
 	LrDate string `json:"lrDate"`
@@ -223,57 +223,57 @@ type Job struct {
 
 	// A method used for forever-incremental backup.
 	// Possible values: NONE, HYPERVISOR, DOUBLE_CHECK, PROPRIETARY
-	DifferentialTrackingMode string `json:"differentialTrackingMode"`
+	DifferentialTrackingMode DifferentialTrackingMode `json:"differentialTrackingMode"`
 
 	// The mode of execution of pre-job scripts.
 	// Possible values: NEVER, ALWAYS
-	PreScriptExecutionMode string `json:"preScriptExecutionmode"`
+	PreScriptExecutionMode ScriptExecutionMode `json:"preScriptExecutionmode"`
 
 	// Job behavior: either to wait for the script to finish or proceed.
 	// Possible values: NONE, WAIT, PROCEED
-	PreScriptBehavior string `json:"preScriptBehavior"`
+	PreScriptBehavior ScriptBehavior `json:"preScriptBehavior"`
 
 	// The job behavior on pre-job script failure.
 	// Possible values: NONE, FAIL, SKIP
-	PreScriptErrorMode string `json:"preScriptErrorMode"`
+	PreScriptErrorMode ScriptErrorMode `json:"preScriptErrorMode"`
 
 	// The path to the pre-job script
 	PreScriptPath string `json:"preScriptPath"`
 
 	// The mode of execution of post-job scripts.
 	// Possible values: NEVER, ALWAYS
-	PostScriptExecutionMode string `json:"postScriptExecutionMode"`
+	PostScriptExecutionMode ScriptExecutionMode `json:"postScriptExecutionMode"`
 
 	// Job behavior: either to wait for the script to finish or proceed.
 	// Possible values: NONE, WAIT, PROCEED
-	PostScriptBehavior string `json:"postScriptBehavior"`
+	PostScriptBehavior ScriptBehavior `json:"postScriptBehavior"`
 
 	// The job behavior on post-job script failure.
 	// Possible values: NONE, FAIL, SKIP
-	PostScriptErrorMode string `json:"postScriptErrorMode"`
+	PostScriptErrorMode ScriptErrorMode `json:"postScriptErrorMode"`
 
 	// For replication: thin disk or respect source.
 	// Possible values: AUTO, FORCE_THIN
-	ThinDiskMode string `json:"thinDiskMode"`
+	ThinDiskMode ThinDiskMode `json:"thinDiskMode"`
 
 	// For AWS jobs only: a type of EBS volume.
 	// Possible values: AUTO, FORCE_MAGNETIC
-	EbsVolumeMode string `json:"ebsVolumeMode"`
+	EbsVolumeMode EbsVolumeMode `json:"ebsVolumeMode"`
 
 	// For AWS jobs only: a type of a temporary volume
 	TemporaryVolumeType string `json:"temporaryVolumeType"`
 
 	// Network acceleration mode.
 	// Possible values: NONE, AUTO, FAST, MEDIUM, BEST
-	NetworkAccelerationMode string `json:"networkAccelerationMode"`
+	NetworkAccelerationMode NetworkAccelerationMode `json:"networkAccelerationMode"`
 
 	// Encryption mode.
 	// Possible values: NONE, NORMAL
-	EncryptionMode string `json:"encryptionMode"`
+	EncryptionMode EncryptionMode `json:"encryptionMode"`
 
 	// Application-aware mode.
 	// Possible values: NONE, VSS_IGNORE_ERRORS, VSS_FAIL_ON_ERRORS
-	ApplicationAwareMode string `json:"applicationAwareMode"`
+	ApplicationAwareMode ApplicationAwareMode `json:"applicationAwareMode"`
 
 	// Retention policy options.
 	RetentionPolicy RetentionPolicy `json:"retentionPolicy"`
@@ -287,19 +287,19 @@ type Job struct {
 
 	// Recovery type.
 	// Possible values: SYNTHETIC, PRODUCTION
-	RecoveryType string `json:"recoveryType"`
+	RecoveryType RecoveryType `json:"recoveryType"`
 
 	// The mode of data transfer.
 	// Possible values: AUTO, SAN, LAN, HOT_ADD
-	TransporterMode string `json:"transporterMode"`
+	TransporterMode TransporterMode `json:"transporterMode"`
 
 	// Mode of Microsoft Exchange log truncation.
 	// Possible values: NONE, ALWAYS, JOB_SUCCESS
-	ExchangelogTruncationMode string `json:"exchangeLogTruncationMode"`
+	ExchangelogTruncationMode LogTruncationMode `json:"exchangeLogTruncationMode"`
 
 	// The mode of screenshot verification.
 	// Possible values: NEVER, ALWAYS
-	ScreenshotVerificationMode string `json:"screenshotVerificationMode"`
+	ScreenshotVerificationMode ScreenshotVerificationMode `json:"screenshotVerificationMode"`
 
 	// Source objects
 	Objects []Object `json:"objects"`
@@ -315,6 +315,9 @@ type Job struct {
 
 	// If the job is locked, lock reasons
 	LockReasons []interface{} `json:"lockReasons,omitempty"`
+
+	// clientTimeOffset the dates of the job were requested with
+	clientTimeOffset int
 }
 
 type Object struct {
@@ -323,7 +326,7 @@ type Object struct {
 
 	// The state of the last job run.
 	// Possible values: SCHEDULED, DEMAND, WAITING, RUNNING, STOPPED, FAILED, SUCCEEDED, SKIPPED
-	LrState string `json:"lrState"`
+	LrState ObjectState `json:"lrState"`
 
 	// The speed of the last job run
 	LrSpeed int64 `json:"lrSpeed"`
@@ -336,7 +339,7 @@ type Object struct {
 
 	// Current state.
 	// Possible values: SCHEDULED, DEMAND, WAITING, RUNNING, STOPPED, FAILED, SUCCEEDED, SKIPPED
-	CrState string `json:"lrState"`
+	CrState ObjectState `json:"crState"`
 
 	// The progress of the current job
 	CrProgress int `json:"crProgress"`
@@ -349,7 +352,7 @@ type Object struct {
 
 	// Source object power state.
 	// Possible values: ON, OFF, SUSPENDED, UNKNOWN
-	SourcePowerState string `json:"sourcePowerState"`
+	SourcePowerState PowerState `json:"sourcePowerState"`
 
 	// Source object subtype
 	SourceSubType string `json:"sourceSubType"`
@@ -362,32 +365,32 @@ type Object struct {
 
 	// Target object power state.
 	// Possible values: ON, OFF, SUSPENDED, UNKNOWN
-	TargetPowerState string `json:"targetPowerState"`
+	TargetPowerState PowerState `json:"targetPowerState"`
 
 	// Target object subtype
 	TargetSubType string `json:"targetSubType"`
 
 	// The state of verification.
 	// Possible values: SCHEDULED, DEMAND, WAITING, RUNNING, STOPPED, FAILED, SUCCEEDED, SKIPPED
-	VerificationState string `json:"verificationState"`
+	VerificationState ObjectState `json:"verificationState"`
 
 	// The name of the screenshot used for verification
 	ScreenshotName string `json:"screenshotName"`
 
 	// The state of the flash boot.
 	// Possible values: WAITING, STARTING, RUNNING_VM, FAILED, DISCARDING, DISCARDED
-	FlashBootState string `json:"flashBootState"`
+	FlashBootState FlashBootState `json:"flashBootState"`
 
 	// Bandwidth limit for the last job run
 	LrBandwidthLimit int64 `json:"lrBandwidthLimit"`
 
 	// Transporter modes for the last job run.
 	// Possible values: AUTO, SAN, LAN, HOT_ADD
-	LrTransporterModes []interface{} `json:"lrTransporterModes"`
+	LrTransporterModes []TransporterMode `json:"lrTransporterModes"`
 
 	// Transporter modes for the current job run.
 	// Possible values: AUTO, SAN, LAN, HOT_ADD
-	CrTransporterModes []interface{} `json:"crTransporterModes"`
+	CrTransporterModes []TransporterMode `json:"crTransporterModes"`
 
 	// Screenshot path
 	ScreenshotPath string `json:"screenshotPath"`
@@ -419,7 +422,7 @@ type Schedule struct {
 
 	// The type of backup schedule
 	// Possible values: DAILY, PERIODICALLY, NONE, MONTHLY_YEARLY, TRIGGER
-	Type string `json:"type"`
+	Type ScheduleType `json:"type"`
 
 	// Priority of the schedule
 	Position int `json:"position"`
@@ -442,7 +445,7 @@ type Schedule struct {
 	// If type is PERIODICALLY or TRIGGERED, defines the delay unit between jobs. For example, "Run job
 	// every 30 minutes".
 	// Possible values: DAY (for PERIODICALLY only), SECOND, MINUTE, HOUR
-	EveryType string `json:"everyType"`
+	EveryType EveryType `json:"everyType"`
 
 	// The number of delay units between job runs.
 	// For example, "Run job every 30 minutes"
@@ -450,7 +453,7 @@ type Schedule struct {
 
 	// If type is MONTHLY_YEARLY , selects the number of a weekday in a month or a day number
 	// Possible values: FIRST, SECOND, THIRD, FOURTH, LAST, DAY
-	MonthlyEveryType string `json:"monthlyEveryType"`
+	MonthlyEveryType MonthlyEveryType `json:"monthlyEveryType"`
 
 	// If monthlyEveryType is DAY, selects the day number in a month
 	DayOfMonth int `json:"dayOfMonth"`
@@ -466,11 +469,11 @@ type Schedule struct {
 
 	// Selects either to run the job immediately after the previous or within a delay. If DELAYED is
 	// selected, the delay is defined by the everyType and every fields
-	TriggerRunType string `json:"triggerRunType"`
+	TriggerRunType TriggerRunType `json:"triggerRunType"`
 
 	// Trigger job conditions
 	// RUN_SUCCESS, RUN_FAILURE, RUN_STOP
-	TriggerEvents []interface{} `json:"triggerEvents"`
+	TriggerEvents []TriggerEvent `json:"triggerEvents"`
 
 	// Time and date of the next job run
 	// YYYY-MM-DDTHH:MM:SS.SSSZ
@@ -490,26 +493,16 @@ type Schedule struct {
 
 	// Relative time of the next job run in ms
 	NextRunRelative int64 `json:"nextRunRelative"`
+
+	// clientTimeOffset the dates of the schedule were requested with
+	clientTimeOffset int
 }
 
 func (s *JobService) JobInfo(ctx context.Context, ids []int, clientTimeOffset int) (*Jobs, *http.Response, error) {
-	request := Request{
-		Action: JobAction,
-		Method: "getJobInfo",
-		Data:   []interface{}{ids, clientTimeOffset},
-		Type:   "rpc",
-		Tid:    1,
-	}
-
-	req, err := s.client.NewRequest(&request)
-	if err != nil {
-		return nil, nil, err
-	}
-	var jobs Jobs
-	r := Response{Data: &jobs}
-	resp, err := s.client.Do(ctx, req, &r)
+	jobs, resp, err := Invoke[Jobs](ctx, s.client, JobAction, "getJobInfo", ids, clientTimeOffset)
 	if err != nil {
 		return nil, resp, err
 	}
-	return &jobs, resp, nil
+	jobs.setClientTimeOffset(clientTimeOffset)
+	return jobs, resp, nil
 }
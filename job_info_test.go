@@ -0,0 +1,65 @@
+package nakivo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/peertechde/go-nakivo"
+)
+
+func TestJobInfo(t *testing.T) {
+	client := fixtureClient(t, "job_info")
+	ctx := context.Background()
+
+	if _, _, err := client.Authentication.Login(ctx, "admin", "secret", false); err != nil {
+		t.Fatal(err)
+	}
+	jobs, _, err := client.Job.JobInfo(ctx, []int{10, 11, 12}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs.Children) != 2 {
+		t.Fatalf("expected 2 jobs, got %d", len(jobs.Children))
+	}
+
+	web, postgres := jobs.Children[0], jobs.Children[1]
+	if web.Id != 10 || web.Name != "Web servers" || web.JobType != nakivo.JobTypeBackup {
+		t.Errorf("unexpected job %d %q of type %s", web.Id, web.Name, web.JobType)
+	}
+	if web.LrState != nakivo.JobStateOK {
+		t.Errorf("expected last run state OK, got %s", web.LrState)
+	}
+	start, err := web.LastRunStart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	finish, err := web.LastRunFinish()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 3, 4, 22, 0, 0, 0, time.UTC); !start.Equal(want) {
+		t.Errorf("expected the last run to start at %s, got %s", want, start)
+	}
+	if want := time.Date(2024, 3, 4, 22, 40, 0, 0, time.UTC); !finish.Equal(want) {
+		t.Errorf("expected the last run to finish at %s, got %s", want, finish)
+	}
+	if web.AverageDuration() != 45*time.Minute {
+		t.Errorf("expected average duration 45m, got %s", web.AverageDuration())
+	}
+
+	if len(web.Schedules) != 1 || web.Schedules[0].Type != nakivo.ScheduleTypeDaily {
+		t.Fatalf("unexpected schedules %+v", web.Schedules)
+	}
+	next, err := web.Schedules[0].NextRunAt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 3, 5, 22, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Errorf("expected next run at %s, got %s", want, next)
+	}
+
+	if len(postgres.Schedules) != 1 || postgres.Schedules[0].TriggerItem != web.Vid {
+		t.Errorf("expected a trigger by %s, got %+v", web.Vid, postgres.Schedules)
+	}
+}
@@ -0,0 +1,128 @@
+package nakivo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const (
+	JobManagementAction = "JobManagement"
+)
+
+// RunType selects the source objects processed by an ad-hoc job run.
+type RunType string
+
+const (
+	// RunAll runs all source objects of a job
+	RunAll RunType = "ALL"
+
+	// RunSelected runs only the source objects selected by RunOptions.ObjectVids
+	RunSelected RunType = "SELECTED"
+)
+
+func (v RunType) String() string {
+	return string(v)
+}
+
+// Valid reports whether v is one of the documented values.
+func (v RunType) Valid() bool {
+	return v == RunAll || v == RunSelected
+}
+
+// RunOptions controls an ad-hoc job run.
+type RunOptions struct {
+	// Vids of the source objects to run. Empty runs all objects of the jobs.
+	ObjectVids []string
+
+	// Full forces a full backup instead of an incremental one.
+	Full bool
+
+	// Offset of the time zone of the client in ms, applied to the dates of the returned jobs
+	ClientTimeOffset int
+}
+
+type runJobParams struct {
+	JobIds          []int    `json:"jobId"`
+	RunType         RunType  `json:"runType"`
+	ObjectVids      []string `json:"objectVids,omitempty"`
+	ForceFullBackup bool     `json:"forceFullBackup,omitempty"`
+}
+
+type stopJobParams struct {
+	JobIds []int `json:"jobId"`
+}
+
+// Run starts an ad-hoc run of the jobs with ids and returns the jobs in their resulting state.
+func (s *JobService) Run(ctx context.Context, ids []int, opts *RunOptions) (*Jobs, *http.Response, error) {
+	if len(ids) == 0 {
+		return nil, nil, fmt.Errorf("no jobs to run")
+	}
+	if opts == nil {
+		opts = &RunOptions{}
+	}
+	params := runJobParams{
+		JobIds:          ids,
+		RunType:         RunAll,
+		ForceFullBackup: opts.Full,
+	}
+	if len(opts.ObjectVids) > 0 {
+		params.RunType = RunSelected
+		params.ObjectVids = opts.ObjectVids
+	}
+
+	if _, resp, err := s.client.Call(ctx, JobManagementAction, "runJob", []interface{}{params}, nil); err != nil {
+		return nil, resp, err
+	}
+	return s.JobInfo(ctx, ids, opts.ClientTimeOffset)
+}
+
+// Stop stops the running jobs with ids and returns the jobs in their resulting state, with the
+// dates requested for the client time offset in ms.
+func (s *JobService) Stop(ctx context.Context, ids []int, clientTimeOffset int) (*Jobs, *http.Response, error) {
+	if len(ids) == 0 {
+		return nil, nil, fmt.Errorf("no jobs to stop")
+	}
+	params := stopJobParams{JobIds: ids}
+
+	if _, resp, err := s.client.Call(ctx, JobManagementAction, "stopJob", []interface{}{params}, nil); err != nil {
+		return nil, resp, err
+	}
+	return s.JobInfo(ctx, ids, clientTimeOffset)
+}
+
+// RunGroup starts an ad-hoc run of all jobs in the group with id and its sub groups.
+func (s *JobService) RunGroup(ctx context.Context, id int, opts *RunOptions) (*Jobs, *http.Response, error) {
+	ids, resp, err := s.groupJobIds(ctx, id)
+	if err != nil {
+		return nil, resp, err
+	}
+	return s.Run(ctx, ids, opts)
+}
+
+// StopGroup stops all running jobs in the group with id and its sub groups, see Stop.
+func (s *JobService) StopGroup(ctx context.Context, id int, clientTimeOffset int) (*Jobs, *http.Response, error) {
+	ids, resp, err := s.groupJobIds(ctx, id)
+	if err != nil {
+		return nil, resp, err
+	}
+	return s.Stop(ctx, ids, clientTimeOffset)
+}
+
+// groupJobIds returns the ids of all jobs in the group with id and its sub groups.
+func (s *JobService) groupJobIds(ctx context.Context, id int) ([]int, *http.Response, error) {
+	groups, resp, err := s.listGroups(ctx, []int{id}, 0, true)
+	if err != nil {
+		return nil, resp, err
+	}
+	for _, group := range groups.Children {
+		if group.Id != id {
+			continue
+		}
+		if len(group.ChildJobIds) == 0 {
+			return nil, resp, &Error{Action: JobAction, Method: "getGroupInfo", Kind: ErrNotFound, Err: fmt.Errorf("group %d contains no jobs", id)}
+		}
+		return group.ChildJobIds, resp, nil
+	}
+	return nil, resp, &Error{Action: JobAction, Method: "getGroupInfo", Kind: ErrNotFound, Err: fmt.Errorf("group %d not found", id)}
+}
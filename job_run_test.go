@@ -0,0 +1,79 @@
+package nakivo_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/peertechde/go-nakivo"
+)
+
+// recordOffsets returns middleware collecting the client time offsets of getJobInfo into offsets.
+func recordOffsets(offsets *[]int) nakivo.Middleware {
+	return func(next nakivo.Handler) nakivo.Handler {
+		return func(ctx context.Context, ex *nakivo.Exchange) error {
+			for _, request := range ex.Requests {
+				if request.Method != "getJobInfo" {
+					continue
+				}
+				params := requestParams(request)
+				var offset int
+				json.Unmarshal(params[1], &offset)
+				*offsets = append(*offsets, offset)
+			}
+			return next(ctx, ex)
+		}
+	}
+}
+
+func TestRunAndStop(t *testing.T) {
+	srv := newDirector()
+	defer srv.Close()
+
+	var offsets []int
+	client := loggedIn(t, srv, nakivo.WithMiddleware(recordOffsets(&offsets)))
+	ctx := context.Background()
+	const offset = 2 * 60 * 60 * 1000
+
+	jobs, _, err := client.Job.Run(ctx, []int{10}, &nakivo.RunOptions{ClientTimeOffset: offset})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs.Children) != 1 || !jobs.Children[0].CrState.IsRunning() {
+		t.Fatalf("expected job 10 to run, got %+v", jobs.Children)
+	}
+	jobs, _, err = client.Job.Stop(ctx, []int{10}, offset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs.Children) != 1 || jobs.Children[0].CrState.IsRunning() {
+		t.Fatalf("expected job 10 to be stopped, got %+v", jobs.Children)
+	}
+	if len(offsets) != 2 || offsets[0] != offset || offsets[1] != offset {
+		t.Errorf("expected the jobs to be requested with offset %d, got %v", offset, offsets)
+	}
+}
+
+func TestRunGroup(t *testing.T) {
+	srv := newDirector()
+	defer srv.Close()
+	srv.AddGroup(nakivo.Group{Id: 3, Vid: "JobGroup-3", Name: "Empty"}, 0)
+
+	client := loggedIn(t, srv)
+	ctx := context.Background()
+
+	jobs, _, err := client.Job.RunGroup(ctx, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs.Children) != 2 {
+		t.Fatalf("expected the jobs of the group and its sub group to run, got %+v", jobs.Children)
+	}
+	if _, _, err := client.Job.StopGroup(ctx, 3, 0); !errors.Is(err, nakivo.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for an empty group, got %v", err)
+	}
+	if _, _, err := client.Job.RunGroup(ctx, 42, nil); !errors.Is(err, nakivo.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for an unknown group, got %v", err)
+	}
+}
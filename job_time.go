@@ -0,0 +1,149 @@
+package nakivo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// TimeLayout is the layout of the timestamps of the director, e.g. 2021-03-01T17:30:00.000Z.
+	TimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+	// ClockLayout is the layout of the start and end times of a schedule, e.g. 05:30:00 PM.
+	ClockLayout = "03:04:05 PM"
+)
+
+// ParseTime parses a timestamp of the director. The director renders timestamps as the wall
+// clock of the client, whose time zone is given by the clientTimeOffset of the request, but
+// marks them as UTC. ParseTime therefore reads the wall clock in a fixed zone with the offset,
+// so the wall clock reads as shown by the director and the instant is correct.
+//
+// The offset is in ms east of UTC, e.g. 3600000 for UTC+1 and -18000000 for UTC-5, which is the
+// negated value of getTimezoneOffset of JavaScript in ms. ClientTimeOffset returns the offset of
+// a time zone in this convention. Timestamps with an explicit offset other than UTC and those
+// requested with an offset of zero are returned as is. An empty or "null" timestamp is the zero
+// time.
+func ParseTime(value string, clientTimeOffset int) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "null" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		// some directors omit the zone designator
+		var lerr error
+		if t, lerr = time.Parse("2006-01-02T15:04:05.999999999", value); lerr != nil {
+			return time.Time{}, fmt.Errorf("invalid timestamp %q (%s)", value, err)
+		}
+	}
+	if _, offset := t.Zone(); clientTimeOffset == 0 || offset != 0 {
+		return t, nil
+	}
+	zone := time.FixedZone("", clientTimeOffset/1000)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), zone), nil
+}
+
+// ClientTimeOffset returns the offset of the time zone of t at t in ms east of UTC, as expected
+// by the clientTimeOffset of List, JobInfo and ParseTime.
+func ClientTimeOffset(t time.Time) int {
+	_, offset := t.Zone()
+	return offset * 1000
+}
+
+// ParseClock parses a time of day like 05:30:00 PM and returns it as the time since midnight.
+// Seconds may be omitted. An empty or "null" time of day is reported as ok == false.
+func ParseClock(value string) (d time.Duration, ok bool, err error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if value == "" || value == "NULL" {
+		return 0, false, nil
+	}
+	for _, layout := range []string{ClockLayout, "3:04:05 PM", "03:04 PM", "3:04 PM", "15:04:05", "15:04"} {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+				time.Duration(t.Second())*time.Second, true, nil
+		}
+	}
+	return 0, false, fmt.Errorf("invalid time of day %q", value)
+}
+
+// setClientTimeOffset records the offset the dates of jobs were requested with.
+func (jobs *Jobs) setClientTimeOffset(clientTimeOffset int) {
+	for i := range jobs.Children {
+		job := &jobs.Children[i]
+		job.clientTimeOffset = clientTimeOffset
+		for j := range job.Schedules {
+			job.Schedules[j].clientTimeOffset = clientTimeOffset
+		}
+	}
+}
+
+// AddedAt returns the date the job got added.
+func (job *Job) AddedAt() (time.Time, error) {
+	return ParseTime(job.Added, job.clientTimeOffset)
+}
+
+// UpdatedAt returns the date the job got updated.
+func (job *Job) UpdatedAt() (time.Time, error) {
+	return ParseTime(job.Updated, job.clientTimeOffset)
+}
+
+// CurrentRunStart returns the start of the current job run, the zero time if the job isn't
+// running.
+func (job *Job) CurrentRunStart() (time.Time, error) {
+	return ParseTime(job.CrDate, job.clientTimeOffset)
+}
+
+// LastRunStart returns the start of the last job run, the zero time if the job never ran.
+func (job *Job) LastRunStart() (time.Time, error) {
+	return ParseTime(job.LrDate, job.clientTimeOffset)
+}
+
+// LastRunFinish returns the end of the last job run, the zero time if the job never ran.
+func (job *Job) LastRunFinish() (time.Time, error) {
+	return ParseTime(job.LrFinishDate, job.clientTimeOffset)
+}
+
+// LastRunDuration returns the duration of the last job run.
+func (job *Job) LastRunDuration() time.Duration {
+	return time.Duration(job.LrDurationMs) * time.Millisecond
+}
+
+// AverageDuration returns the average duration of the job runs.
+func (job *Job) AverageDuration() time.Duration {
+	return time.Duration(job.AverageDurationMs) * time.Millisecond
+}
+
+// LastRunDuration returns the data copy duration of the object during the last job run.
+func (object *Object) LastRunDuration() time.Duration {
+	return time.Duration(object.LrDuration) * time.Millisecond
+}
+
+// CurrentRunDuration returns the data copy duration of the object during the current job run.
+func (object *Object) CurrentRunDuration() time.Duration {
+	return time.Duration(object.CrDuration) * time.Millisecond
+}
+
+// NextRunAt returns the time and date of the next job run, the zero time if none is planned.
+func (schedule *Schedule) NextRunAt() (time.Time, error) {
+	return ParseTime(schedule.NextRun, schedule.clientTimeOffset)
+}
+
+// EffectiveFrom returns the date the schedule is effective from, the zero time if the
+// schedule is effective from now.
+func (schedule *Schedule) EffectiveFrom() (time.Time, error) {
+	return ParseTime(schedule.EffectiveDate, schedule.clientTimeOffset)
+}
+
+// StartClock returns the start time of the schedule as the time since midnight in the time
+// zone of the schedule.
+func (schedule *Schedule) StartClock() (d time.Duration, ok bool, err error) {
+	return ParseClock(schedule.StartTime)
+}
+
+// EndClock returns the end time of the schedule as the time since midnight in the time zone
+// of the schedule, ok is false if no end time is set.
+func (schedule *Schedule) EndClock() (d time.Duration, ok bool, err error) {
+	return ParseClock(schedule.EndTime)
+}
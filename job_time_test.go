@@ -0,0 +1,103 @@
+package nakivo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peertechde/go-nakivo"
+)
+
+func TestParseTime(t *testing.T) {
+	const hour = 60 * 60 * 1000
+	tests := []struct {
+		value  string
+		offset int
+		want   time.Time
+	}{
+		{value: "", want: time.Time{}},
+		{value: "null", offset: hour, want: time.Time{}},
+		{value: "2024-03-04T22:00:00.000Z", want: time.Date(2024, 3, 4, 22, 0, 0, 0, time.UTC)},
+		// the wall clock is in the zone of the client, east of UTC for positive offsets
+		{value: "2024-03-04T22:00:00.000Z", offset: hour, want: time.Date(2024, 3, 4, 21, 0, 0, 0, time.UTC)},
+		{value: "2024-03-04T22:00:00.000Z", offset: -5 * hour, want: time.Date(2024, 3, 5, 3, 0, 0, 0, time.UTC)},
+		{value: "2024-03-04T22:00:00.000Z", offset: 5*hour + 30*60*1000, want: time.Date(2024, 3, 4, 16, 30, 0, 0, time.UTC)},
+		{value: "2024-03-04T22:00:00.250", offset: hour, want: time.Date(2024, 3, 4, 21, 0, 0, 250e6, time.UTC)},
+		// an explicit offset is kept
+		{value: "2024-03-04T22:00:00.000+02:00", offset: hour, want: time.Date(2024, 3, 4, 20, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := nakivo.ParseTime(tt.value, tt.offset)
+		if err != nil {
+			t.Errorf("ParseTime(%q, %d) failed (%s)", tt.value, tt.offset, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTime(%q, %d) = %s, want %s", tt.value, tt.offset, got, tt.want.UTC())
+		}
+		if !tt.want.IsZero() && tt.offset != 0 && tt.value[len(tt.value)-1] == 'Z' {
+			if _, offset := got.Zone(); offset*1000 != tt.offset {
+				t.Errorf("ParseTime(%q, %d) is in zone %s", tt.value, tt.offset, got.Location())
+			}
+		}
+	}
+
+	if _, err := nakivo.ParseTime("yesterday", 0); err == nil {
+		t.Error("expected an error for an invalid timestamp")
+	}
+}
+
+func TestClientTimeOffset(t *testing.T) {
+	tests := []struct {
+		loc  *time.Location
+		want int
+	}{
+		{loc: time.UTC, want: 0},
+		{loc: time.FixedZone("CET", 60*60), want: 3600000},
+		{loc: time.FixedZone("EST", -5*60*60), want: -18000000},
+	}
+	for _, tt := range tests {
+		now := time.Date(2024, 3, 4, 22, 0, 0, 0, tt.loc)
+		offset := nakivo.ClientTimeOffset(now)
+		if offset != tt.want {
+			t.Errorf("ClientTimeOffset in %s = %d, want %d", tt.loc, offset, tt.want)
+		}
+
+		// the director renders the wall clock of the client marked as UTC
+		rendered := now.Format("2006-01-02T15:04:05.000") + "Z"
+		parsed, err := nakivo.ParseTime(rendered, offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !parsed.Equal(now) {
+			t.Errorf("ParseTime(%q, %d) = %s, want %s", rendered, offset, parsed, now)
+		}
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{value: "", ok: false},
+		{value: "null", ok: false},
+		{value: "12:00:00 AM", want: 0, ok: true},
+		{value: "05:30:00 PM", want: 17*time.Hour + 30*time.Minute, ok: true},
+		{value: "9:15 am", want: 9*time.Hour + 15*time.Minute, ok: true},
+		{value: "23:59:30", want: 23*time.Hour + 59*time.Minute + 30*time.Second, ok: true},
+	}
+	for _, tt := range tests {
+		got, ok, err := nakivo.ParseClock(tt.value)
+		if err != nil {
+			t.Errorf("ParseClock(%q) failed (%s)", tt.value, err)
+			continue
+		}
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseClock(%q) = %s, %t, want %s, %t", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+	if _, _, err := nakivo.ParseClock("25:00"); err == nil {
+		t.Error("expected an error for an invalid time of day")
+	}
+}
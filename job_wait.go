@@ -0,0 +1,202 @@
+package nakivo
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// defaultWaitInterval is the interval a job is polled at while waiting for it.
+const defaultWaitInterval = 10 * time.Second
+
+// WaitOptions controls how Wait polls a job.
+type WaitOptions struct {
+	// Interval between two polls. Defaults to 10 seconds.
+	Interval time.Duration
+
+	// Offset of the time zone of the client in ms, passed to JobInfo
+	ClientTimeOffset int
+
+	// Since, if set, is the time the awaited run was requested at, e.g. right before calling
+	// Run. Wait keeps polling until it saw the job running or the last run of the job started
+	// at or after Since, so the result of the previous run isn't returned while the director
+	// hasn't started the run yet. Since is compared to the clock of the director.
+	Since time.Time
+
+	// OnProgress, if set, is called with the progress of the run after every poll.
+	OnProgress func(Progress)
+
+	// Progress, if set, receives the progress of the run after every poll. Wait blocks until
+	// the progress is received or the context is done.
+	Progress chan<- Progress
+}
+
+// Progress is the state of a running job.
+type Progress struct {
+	// Id of the job
+	JobId int
+
+	// Current state of the job
+	State JobState
+
+	// Progress of the current run in percent
+	Progress int
+
+	// The number of machines queued for processing
+	VmPlanned int
+
+	// The number of successfully processed machines
+	VmOk int
+
+	// The number of failed machines
+	VmFailed int
+
+	// The number of stopped machines
+	VmStopped int
+}
+
+// RunResult summarizes a finished job run.
+type RunResult struct {
+	// Job as of the end of the run
+	Job Job
+
+	// State of the run, one of JobStateOK, JobStateFailed or JobStateStopped
+	State JobState
+
+	// Duration of the run
+	Duration time.Duration
+
+	// Data transferred during the run in KB
+	DataKb int64
+
+	// Number of successfully processed machines
+	VmOk int
+
+	// Number of failed machines
+	VmFailed int
+
+	// Number of stopped machines
+	VmStopped int
+
+	// Source objects which failed
+	FailedObjects []Object
+}
+
+// Succeeded reports whether the run finished successfully.
+func (r *RunResult) Succeeded() bool {
+	return r.State == JobStateOK
+}
+
+// Wait polls the job with id until its current run finished and returns the result of the
+// run. If the job isn't running when Wait is called, the result of its last run is returned,
+// unless WaitOptions.Since is set.
+func (s *JobService) Wait(ctx context.Context, id int, opts *WaitOptions) (*RunResult, error) {
+	if opts == nil {
+		opts = &WaitOptions{}
+	}
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = defaultWaitInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	seen := opts.Since.IsZero()
+	for {
+		job, err := s.job(ctx, id, opts.ClientTimeOffset)
+		if err != nil {
+			return nil, err
+		}
+		if job.CrState.IsRunning() {
+			seen = true
+			if err := report(ctx, job, opts); err != nil {
+				return nil, err
+			}
+		} else {
+			if !seen {
+				// the run may not have been started yet
+				if seen, err = startedSince(job, opts.Since); err != nil {
+					return nil, err
+				}
+			}
+			if seen {
+				return runResult(job), nil
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// report hands the progress of the running job to the callback and channel of opts.
+func report(ctx context.Context, job *Job, opts *WaitOptions) error {
+	progress := Progress{
+		JobId:     job.Id,
+		State:     job.CrState,
+		Progress:  job.CrProgress,
+		VmPlanned: job.CrVmPlanned,
+		VmOk:      job.CrVmOk,
+		VmFailed:  job.CrVmFailed,
+		VmStopped: job.CrVmStopped,
+	}
+	if opts.OnProgress != nil {
+		opts.OnProgress(progress)
+	}
+	if opts.Progress != nil {
+		select {
+		case opts.Progress <- progress:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// job returns the job with id.
+func (s *JobService) job(ctx context.Context, id int, clientTimeOffset int) (*Job, error) {
+	jobs, _, err := s.JobInfo(ctx, []int{id}, clientTimeOffset)
+	if err != nil {
+		return nil, err
+	}
+	for i := range jobs.Children {
+		if jobs.Children[i].Id == id {
+			return &jobs.Children[i], nil
+		}
+	}
+	return nil, &Error{Action: JobAction, Method: "getJobInfo", Kind: ErrNotFound, Err: fmt.Errorf("job %d not found", id)}
+}
+
+// startedSince reports whether the last run of job started at or after since. The director
+// reports dates to the millisecond.
+func startedSince(job *Job, since time.Time) (bool, error) {
+	if !job.HasLastRun {
+		return false, nil
+	}
+	start, err := job.LastRunStart()
+	if err != nil {
+		return false, err
+	}
+	return !start.Before(since.Truncate(time.Millisecond)), nil
+}
+
+func runResult(job *Job) *RunResult {
+	result := &RunResult{
+		Job:       *job,
+		State:     job.LrState,
+		Duration:  job.LastRunDuration(),
+		DataKb:    job.LrDataKb,
+		VmOk:      job.LrVmOk,
+		VmFailed:  job.LrVmFailed,
+		VmStopped: job.LrVmStopped,
+	}
+	for _, object := range job.Objects {
+		if object.LrState == ObjectStateFailed {
+			result.FailedObjects = append(result.FailedObjects, object)
+		}
+	}
+	return result
+}
@@ -0,0 +1,125 @@
+package nakivo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/peertechde/go-nakivo"
+)
+
+type waitResult struct {
+	result *nakivo.RunResult
+	err    error
+}
+
+// wait waits for job id in the background.
+func wait(client *nakivo.Client, id int, opts *nakivo.WaitOptions) <-chan waitResult {
+	done := make(chan waitResult, 1)
+	go func() {
+		result, err := client.Job.Wait(context.Background(), id, opts)
+		done <- waitResult{result: result, err: err}
+	}()
+	return done
+}
+
+func TestWait(t *testing.T) {
+	srv := newDirector()
+	defer srv.Close()
+	client := loggedIn(t, srv)
+
+	if _, _, err := client.Job.Run(context.Background(), []int{10}, nil); err != nil {
+		t.Fatal(err)
+	}
+	srv.SetProgress(10, 50, 1, 0)
+
+	progress := make(chan nakivo.Progress)
+	done := wait(client, 10, &nakivo.WaitOptions{Interval: time.Millisecond, Progress: progress})
+	if p := <-progress; p.JobId != 10 || p.Progress != 50 || p.VmPlanned != 3 || p.VmOk != 1 {
+		t.Errorf("unexpected progress %+v", p)
+	}
+	srv.SetProgress(10, 100, 2, 1)
+	srv.FinishJob(10, nakivo.JobStateFailed)
+	go func() {
+		for range progress {
+		}
+	}()
+
+	r := <-done
+	close(progress)
+	if r.err != nil {
+		t.Fatal(r.err)
+	}
+	if r.result.Succeeded() || r.result.State != nakivo.JobStateFailed || r.result.VmOk != 2 || r.result.VmFailed != 1 {
+		t.Errorf("unexpected result %+v", r.result)
+	}
+}
+
+func TestWaitLastRun(t *testing.T) {
+	srv := newDirector()
+	defer srv.Close()
+	client := loggedIn(t, srv)
+
+	// without Since, the last run is returned if the job isn't running
+	result, err := client.Job.Wait(context.Background(), 10, &nakivo.WaitOptions{Interval: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Succeeded() {
+		t.Errorf("expected the successful last run, got %s", result.State)
+	}
+}
+
+func TestWaitSince(t *testing.T) {
+	srv := newDirector()
+	defer srv.Close()
+
+	var polls int
+	polled := make(chan struct{}, 16)
+	client := loggedIn(t, srv, nakivo.WithMiddleware(func(next nakivo.Handler) nakivo.Handler {
+		return func(ctx context.Context, ex *nakivo.Exchange) error {
+			if ex.Requests[0].Method == "getJobInfo" {
+				select {
+				case polled <- struct{}{}:
+				default:
+				}
+			}
+			return next(ctx, ex)
+		}
+	}))
+
+	since := time.Now()
+	done := wait(client, 10, &nakivo.WaitOptions{Interval: time.Millisecond, Since: since})
+	// the director hasn't started the run yet, so the previous run must not be returned
+	for polls < 3 {
+		select {
+		case r := <-done:
+			t.Fatalf("expected Wait to keep polling, got %+v (%v)", r.result, r.err)
+		case <-polled:
+			polls++
+		}
+	}
+
+	// the run starts and finishes between two polls
+	time.Sleep(2 * time.Millisecond)
+	runner := loggedIn(t, srv)
+	if _, _, err := runner.Job.Run(context.Background(), []int{10}, nil); err != nil {
+		t.Fatal(err)
+	}
+	srv.FinishJob(10, nakivo.JobStateStopped)
+
+	r := <-done
+	if r.err != nil {
+		t.Fatal(r.err)
+	}
+	if r.result.State != nakivo.JobStateStopped {
+		t.Errorf("expected the stopped run, got %s", r.result.State)
+	}
+	start, err := r.result.Job.LastRunStart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start.Before(since.Truncate(time.Millisecond)) {
+		t.Errorf("expected a run started after %s, got %s", since, start)
+	}
+}
@@ -0,0 +1,278 @@
+package nakivo
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// defaultWatchInterval is the interval the director is polled at while watching jobs.
+const defaultWatchInterval = 30 * time.Second
+
+// EventType is the type of a job event.
+type EventType string
+
+const (
+	// JobStarted is emitted when a job starts running
+	JobStarted EventType = "JOB_STARTED"
+
+	// JobProgress is emitted when the progress of a running job changed
+	JobProgress EventType = "JOB_PROGRESS"
+
+	// ObjectFailed is emitted for every source object failing during a run
+	ObjectFailed EventType = "OBJECT_FAILED"
+
+	// JobFinished is emitted when a run of a job finished, successful or not
+	JobFinished EventType = "JOB_FINISHED"
+
+	// JobDisabled is emitted when a job got disabled
+	JobDisabled EventType = "JOB_DISABLED"
+
+	// JobRemoved is emitted when a job got removed
+	JobRemoved EventType = "JOB_REMOVED"
+)
+
+// Event is a change of a job observed by a watcher.
+type Event struct {
+	// Type of the event
+	Type EventType
+
+	// Time the change was observed
+	Time time.Time
+
+	// Job as of the event. For JobRemoved the last known state of the job.
+	Job Job
+
+	// Previous state of the job
+	Previous Job
+
+	// Object which failed, only set for ObjectFailed
+	Object *Object
+}
+
+// WatchOptions controls what and how Watch watches.
+type WatchOptions struct {
+	// Interval between two snapshots. Defaults to 30 seconds.
+	Interval time.Duration
+
+	// Ids of the groups whose jobs, including the jobs of all sub groups, are watched. No ids
+	// watch all jobs.
+	GroupIds []int
+
+	// Offset of the time zone of the client in ms, passed to the director
+	ClientTimeOffset int
+
+	// Size of the buffer of the event channel
+	Buffer int
+
+	// OnError, if set, is called if a snapshot failed, e.g. while the director restarts. The
+	// watcher keeps polling regardless.
+	OnError func(error)
+}
+
+// Watch periodically snapshots the jobs selected by opts and emits an event on the returned
+// channel for every change of their state. The first snapshot serves as baseline and emits no
+// events. Failed snapshots are skipped, so a restart of the director doesn't produce spurious
+// events; an expired session is renewed by the client if it knows the credentials. A job
+// which moved to a group outside of GroupIds is no longer watched, but isn't reported as
+// removed. The channel is closed once ctx is done.
+func (s *JobService) Watch(ctx context.Context, opts *WatchOptions) <-chan Event {
+	if opts == nil {
+		opts = &WatchOptions{}
+	}
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	events := make(chan Event, opts.Buffer)
+
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var previous map[int]*Job
+		for {
+			current, err := s.snapshot(ctx, opts)
+			if err == nil && previous != nil {
+				err = s.resolveMissing(ctx, previous, current, opts.ClientTimeOffset)
+			}
+			switch {
+			case err != nil:
+				if ctx.Err() != nil {
+					return
+				}
+				if opts.OnError != nil {
+					opts.OnError(err)
+				}
+			case previous == nil:
+				previous = current
+			default:
+				for _, event := range diffJobs(previous, current, time.Now()) {
+					select {
+					case events <- event:
+					case <-ctx.Done():
+						return
+					}
+				}
+				previous = current
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// snapshot returns the current state of the watched jobs by id.
+func (s *JobService) snapshot(ctx context.Context, opts *WatchOptions) (map[int]*Job, error) {
+	groups, _, err := s.listGroups(ctx, opts.GroupIds, opts.ClientTimeOffset, true)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[int]bool)
+	var ids []int
+	for _, group := range groups.Children {
+		for _, id := range group.ChildJobIds {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	snapshot := make(map[int]*Job, len(ids))
+	if len(ids) == 0 {
+		return snapshot, nil
+	}
+	jobs, _, err := s.JobInfo(ctx, ids, opts.ClientTimeOffset)
+	if err != nil {
+		return nil, err
+	}
+	for i := range jobs.Children {
+		snapshot[jobs.Children[i].Id] = &jobs.Children[i]
+	}
+	return snapshot, nil
+}
+
+// resolveMissing looks up the jobs of previous which are missing from current. Jobs which still
+// exist, e.g. because they moved to a group which isn't watched, are dropped from previous, so
+// they aren't reported as removed. Jobs the director flags as removed are added to current.
+func (s *JobService) resolveMissing(ctx context.Context, previous, current map[int]*Job, clientTimeOffset int) error {
+	var ids []int
+	for id := range previous {
+		if _, ok := current[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	sort.Ints(ids)
+
+	jobs, _, err := s.JobInfo(ctx, ids, clientTimeOffset)
+	if err != nil {
+		return err
+	}
+	for i := range jobs.Children {
+		job := &jobs.Children[i]
+		if job.IsRemoved {
+			current[job.Id] = job
+		} else {
+			delete(previous, job.Id)
+		}
+	}
+	return nil
+}
+
+// diffJobs returns the events leading from the previous to the current snapshot, ordered by
+// job id.
+func diffJobs(previous, current map[int]*Job, now time.Time) []Event {
+	ids := make([]int, 0, len(previous))
+	for id := range previous {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	var events []Event
+	emit := func(t EventType, job, prev *Job, object *Object) {
+		events = append(events, Event{Type: t, Time: now, Job: *job, Previous: *prev, Object: object})
+	}
+	for _, id := range ids {
+		prev := previous[id]
+		job, ok := current[id]
+		if !ok || job.IsRemoved {
+			// a job flagged as removed is reported once, not again when it disappears
+			if !prev.IsRemoved {
+				if !ok {
+					job = prev
+				}
+				emit(JobRemoved, job, prev, nil)
+			}
+			continue
+		}
+
+		wasRunning, running := prev.CrState.IsRunning(), job.CrState.IsRunning()
+		switch {
+		case !wasRunning && running:
+			emit(JobStarted, job, prev, nil)
+		case wasRunning && running && progressed(prev, job):
+			emit(JobProgress, job, prev, nil)
+		}
+
+		// objects which failed before are already reported
+		reported := map[string]bool{}
+		if wasRunning {
+			reported = failedObjects(prev)
+		}
+		if running {
+			for i := range job.Objects {
+				object := &job.Objects[i]
+				if object.CrState == ObjectStateFailed && !reported[object.Vid] {
+					emit(ObjectFailed, job, prev, object)
+				}
+			}
+		}
+
+		// a run shorter than the interval is only visible by its last run date
+		finished := (wasRunning && !running) || (!running && job.HasLastRun && job.LrDate != prev.LrDate)
+		if finished {
+			for i := range job.Objects {
+				object := &job.Objects[i]
+				if object.LrState == ObjectStateFailed && !reported[object.Vid] {
+					emit(ObjectFailed, job, prev, object)
+				}
+			}
+			emit(JobFinished, job, prev, nil)
+		}
+
+		if prev.IsEnabled && !job.IsEnabled {
+			emit(JobDisabled, job, prev, nil)
+		}
+	}
+	return events
+}
+
+// progressed reports whether the progress of a running job changed.
+func progressed(prev, job *Job) bool {
+	return prev.CrProgress != job.CrProgress ||
+		prev.CrVmOk != job.CrVmOk ||
+		prev.CrVmFailed != job.CrVmFailed ||
+		prev.CrVmStopped != job.CrVmStopped
+}
+
+// failedObjects returns the vids of the objects of job which failed during the current run.
+func failedObjects(job *Job) map[string]bool {
+	failed := make(map[string]bool)
+	for _, object := range job.Objects {
+		if object.CrState == ObjectStateFailed {
+			failed[object.Vid] = true
+		}
+	}
+	return failed
+}
@@ -0,0 +1,198 @@
+package nakivo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/peertechde/go-nakivo"
+)
+
+// idle is a job waiting for its schedule, with a successful last run.
+var idle = nakivo.Job{
+	Id:         10,
+	IsEnabled:  true,
+	CrState:    nakivo.JobStateWaitingSchedule,
+	HasLastRun: true,
+	LrState:    nakivo.JobStateOK,
+	LrDate:     "2024-03-04T22:00:00.000Z",
+	Objects:    []nakivo.Object{{Vid: "vm-1"}, {Vid: "vm-2"}},
+}
+
+// running returns idle in a run with progress and the current state of its objects.
+func running(progress int, states ...nakivo.ObjectState) nakivo.Job {
+	job := idle
+	job.CrState = nakivo.JobStateRunning
+	job.CrDate = "2024-03-05T22:00:00.000Z"
+	job.CrProgress = progress
+	job.Objects = []nakivo.Object{{Vid: "vm-1"}, {Vid: "vm-2"}}
+	for i, state := range states {
+		job.Objects[i].CrState = state
+	}
+	return job
+}
+
+// finished returns idle after a run with the last state of its objects.
+func finished(states ...nakivo.ObjectState) nakivo.Job {
+	job := idle
+	job.LrState = nakivo.JobStateOK
+	job.LrDate = "2024-03-05T22:00:00.000Z"
+	job.Objects = []nakivo.Object{{Vid: "vm-1"}, {Vid: "vm-2"}}
+	for i, state := range states {
+		job.Objects[i].LrState = state
+		if state == nakivo.ObjectStateFailed {
+			job.LrState = nakivo.JobStateFailed
+		}
+	}
+	return job
+}
+
+func TestDiffJobs(t *testing.T) {
+	disabled := idle
+	disabled.IsEnabled = false
+	removed := idle
+	removed.IsRemoved = true
+
+	// event is the type of an event and the vid of its object, if any
+	type event struct {
+		typ    nakivo.EventType
+		object string
+	}
+	tests := []struct {
+		name     string
+		previous *nakivo.Job
+		current  *nakivo.Job
+		want     []event
+	}{
+		{"unchanged", &idle, &idle, nil},
+		{"started", &idle, ptr(running(0)), []event{{nakivo.JobStarted, ""}}},
+		{"started with failed object", &idle, ptr(running(10, nakivo.ObjectStateFailed)), []event{{nakivo.JobStarted, ""}, {nakivo.ObjectFailed, "vm-1"}}},
+		{"progress", ptr(running(10)), ptr(running(50)), []event{{nakivo.JobProgress, ""}}},
+		{"no progress", ptr(running(10)), ptr(running(10)), nil},
+		{"object failed", ptr(running(10, nakivo.ObjectStateRunning)), ptr(running(10, nakivo.ObjectStateFailed)), []event{{nakivo.ObjectFailed, "vm-1"}}},
+		{"object failed before", ptr(running(10, nakivo.ObjectStateFailed)), ptr(running(10, nakivo.ObjectStateFailed)), nil},
+		{"finished", ptr(running(90)), ptr(finished()), []event{{nakivo.JobFinished, ""}}},
+		{"finished with failed object", ptr(running(90, nakivo.ObjectStateSucceeded)), ptr(finished(nakivo.ObjectStateSucceeded, nakivo.ObjectStateFailed)), []event{{nakivo.ObjectFailed, "vm-2"}, {nakivo.JobFinished, ""}}},
+		{"finished with object failed before", ptr(running(90, nakivo.ObjectStateFailed)), ptr(finished(nakivo.ObjectStateFailed)), []event{{nakivo.JobFinished, ""}}},
+		{"short run", &idle, ptr(finished(nakivo.ObjectStateFailed)), []event{{nakivo.ObjectFailed, "vm-1"}, {nakivo.JobFinished, ""}}},
+		{"disabled", &idle, &disabled, []event{{nakivo.JobDisabled, ""}}},
+		{"removed", &idle, nil, []event{{nakivo.JobRemoved, ""}}},
+		{"flagged removed", &idle, &removed, []event{{nakivo.JobRemoved, ""}}},
+		{"flagged removed before", &removed, nil, nil},
+	}
+	now := time.Date(2024, 3, 5, 22, 30, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := map[int]*nakivo.Job{tt.previous.Id: tt.previous}
+			current := map[int]*nakivo.Job{}
+			if tt.current != nil {
+				current[tt.current.Id] = tt.current
+			}
+
+			events := nakivo.DiffJobs(previous, current, now)
+			var got []event
+			for _, e := range events {
+				var vid string
+				if e.Object != nil {
+					vid = e.Object.Vid
+				}
+				got = append(got, event{e.Type, vid})
+				if !e.Time.Equal(now) || e.Previous.Id != tt.previous.Id || e.Job.Id != tt.previous.Id {
+					t.Errorf("unexpected event %+v", e)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected events %v, got %v", tt.want, got)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Fatalf("expected events %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffJobsOrder(t *testing.T) {
+	a, b := idle, idle
+	a.Id, b.Id = 12, 3
+	previous := map[int]*nakivo.Job{a.Id: &a, b.Id: &b}
+	events := nakivo.DiffJobs(previous, map[int]*nakivo.Job{}, time.Now())
+	if len(events) != 2 || events[0].Job.Id != 3 || events[1].Job.Id != 12 {
+		t.Errorf("expected the removals of jobs 3 and 12, got %+v", events)
+	}
+}
+
+func TestWatchMovedJob(t *testing.T) {
+	srv := newDirector()
+	defer srv.Close()
+	srv.AddJob(nakivo.Job{Id: 12, Vid: "Job-12", Name: "MySQL", IsEnabled: true}, 2)
+
+	// exchanges receives the methods of the exchanges of the watcher
+	exchanges := make(chan string, 100)
+	notify := func(next nakivo.Handler) nakivo.Handler {
+		return func(ctx context.Context, ex *nakivo.Exchange) error {
+			err := next(ctx, ex)
+			select {
+			case exchanges <- ex.Requests[0].Method:
+			default:
+			}
+			return err
+		}
+	}
+	client := loggedIn(t, srv, nakivo.WithMiddleware(notify))
+	waitFor := func(method string) {
+		t.Helper()
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case m := <-exchanges:
+				if m == method {
+					return
+				}
+			case <-timeout:
+				t.Fatalf("no %s", method)
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := client.Job.Watch(ctx, &nakivo.WatchOptions{
+		Interval: 5 * time.Millisecond,
+		GroupIds: []int{2},
+		OnError:  func(err error) { t.Error(err) },
+	})
+	// the baseline
+	waitFor("getJobInfo")
+
+	// job 11 leaves the watched group but still exists, job 12 is gone
+	srv.MoveJob(11, 1)
+	srv.RemoveJob(12)
+
+	select {
+	case e := <-events:
+		if e.Type != nakivo.JobRemoved || e.Job.Id != 12 || e.Job.Name != "MySQL" {
+			t.Errorf("expected the removal of job 12, got %s of job %d", e.Type, e.Job.Id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event")
+	}
+
+	// job 11 is no longer watched, so its removal goes unnoticed
+	srv.RemoveJob(11)
+	for len(exchanges) > 0 {
+		<-exchanges
+	}
+	waitFor("getGroupInfo")
+	waitFor("getGroupInfo")
+	select {
+	case e := <-events:
+		t.Errorf("unexpected %s of job %d", e.Type, e.Job.Id)
+	default:
+	}
+}
+
+func ptr(job nakivo.Job) *nakivo.Job {
+	return &job
+}
@@ -0,0 +1,147 @@
+package nakivo
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// redacted replaces secrets in log output.
+const redacted = "[REDACTED]"
+
+// secretParams are the positions of the parameters holding secrets, keyed by action and method.
+var secretParams = map[string]map[string][]int{
+	AuthenticationAction: {
+		"login": {1},
+	},
+}
+
+// WithLogger logs every exchange with the router to logger: successful ones at debug level,
+// failed ones at warn level. Passwords and session cookies are redacted.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) error {
+		c.logger = logger
+		c.middleware = append([]Middleware{LoggingMiddleware(logger)}, c.middleware...)
+		return nil
+	}
+}
+
+// LoggingMiddleware returns middleware logging every exchange to logger. Passwords and session
+// cookies are redacted.
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, ex *Exchange) error {
+			start := time.Now()
+			err := next(ctx, ex)
+
+			attrs := []slog.Attr{
+				slog.Duration("duration", time.Since(start)),
+			}
+			if len(ex.Requests) == 1 {
+				attrs = append(attrs,
+					slog.String("action", ex.Requests[0].Action),
+					slog.String("method", ex.Requests[0].Method),
+					slog.Int("tid", ex.Requests[0].Tid),
+				)
+			} else {
+				attrs = append(attrs, slog.Int("batch", len(ex.Requests)))
+			}
+			if ex.HTTPResponse != nil {
+				attrs = append(attrs, slog.Int("status", ex.HTTPResponse.StatusCode))
+			}
+
+			level := slog.LevelDebug
+			if err != nil {
+				level = slog.LevelWarn
+				attrs = append(attrs, slog.Any("error", err))
+			}
+			if logger.Enabled(ctx, slog.LevelDebug) {
+				if len(ex.Requests) == 1 {
+					attrs = append(attrs, slog.Any("data", redactData(ex.Requests[0])))
+				} else {
+					requests := make([]interface{}, 0, 2*len(ex.Requests))
+					for i, request := range ex.Requests {
+						requests = append(requests, slog.Any(strconv.Itoa(i), request))
+					}
+					attrs = append(attrs, slog.Group("requests", requests...))
+				}
+				if ex.HTTPRequest != nil {
+					attrs = append(attrs, slog.Any("request_header", redactHeader(ex.HTTPRequest.Header)))
+				}
+				if ex.HTTPResponse != nil {
+					attrs = append(attrs, slog.Any("response_header", redactHeader(ex.HTTPResponse.Header)))
+				}
+			}
+			logger.LogAttrs(ctx, level, "nakivo exchange", attrs...)
+			return err
+		}
+	}
+}
+
+// LogValue logs the request with secrets such as the password of a login redacted.
+func (r *Request) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("action", r.Action),
+		slog.String("method", r.Method),
+		slog.Int("tid", r.Tid),
+		slog.Any("data", redactData(r)),
+	)
+}
+
+// redactData returns the data of request with secret parameters replaced.
+func redactData(request *Request) interface{} {
+	positions := secretParams[request.Action][request.Method]
+	if len(positions) == 0 {
+		return request.Data
+	}
+	params, ok := request.Data.([]interface{})
+	if !ok {
+		return redacted
+	}
+	params = append([]interface{}(nil), params...)
+	for _, i := range positions {
+		if i < len(params) {
+			params[i] = redacted
+		}
+	}
+	return params
+}
+
+// redactHeader returns a copy of header with credentials and cookie values replaced. Cookie
+// names are kept.
+func redactHeader(header http.Header) http.Header {
+	h := header.Clone()
+	for key, values := range h {
+		switch key {
+		case "Authorization", "Proxy-Authorization":
+			h[key] = []string{redacted}
+		case "Cookie":
+			for i, v := range values {
+				var cookies []string
+				for _, c := range strings.Split(v, ";") {
+					name, _, _ := strings.Cut(strings.TrimSpace(c), "=")
+					cookies = append(cookies, name+"="+redacted)
+				}
+				values[i] = strings.Join(cookies, "; ")
+			}
+		case "Set-Cookie":
+			for i, v := range values {
+				name, _, _ := strings.Cut(v, "=")
+				values[i] = name + "=" + redacted
+			}
+		}
+	}
+	return h
+}
+
+// warn logs a warning to the logger of the client or the default logger.
+func (c *Client) warn(msg string, args ...interface{}) {
+	logger := c.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.Warn(msg, args...)
+}
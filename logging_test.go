@@ -0,0 +1,117 @@
+package nakivo_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/peertechde/go-nakivo"
+	"github.com/peertechde/go-nakivo/nakivotest"
+)
+
+// sessionCookies returns middleware collecting the values of the cookies set by the director.
+func sessionCookies(values *[]string) nakivo.Middleware {
+	return func(next nakivo.Handler) nakivo.Handler {
+		return func(ctx context.Context, ex *nakivo.Exchange) error {
+			err := next(ctx, ex)
+			if ex.HTTPResponse != nil {
+				for _, cookie := range (&http.Response{Header: ex.HTTPResponse.Header}).Cookies() {
+					*values = append(*values, cookie.Value)
+				}
+			}
+			return err
+		}
+	}
+}
+
+// loggingClient returns a client of srv logging to buf at debug level. The values of the
+// cookies sent and received are collected into cookies.
+func loggingClient(t *testing.T, srv *nakivotest.Server, buf *bytes.Buffer, cookies *[]string) *nakivo.Client {
+	t.Helper()
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	// a cookie of a single sign-on proxy
+	client, err := srv.Client(nakivo.WithLogger(logger), nakivo.WithMiddleware(sessionCookies(cookies)),
+		nakivo.WithHeader("Cookie", "sso=sso-session"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	*cookies = append(*cookies, "sso-session")
+	return client
+}
+
+// assertRedacted checks that the log output neither holds the password nor one of the cookies.
+func assertRedacted(t *testing.T, log string, password string, cookies []string) {
+	t.Helper()
+	if len(cookies) < 2 {
+		t.Fatalf("expected the director to set a session cookie, got %v", cookies)
+	}
+	if strings.Contains(log, password) {
+		t.Errorf("expected the password to be redacted from %s", log)
+	}
+	for _, cookie := range cookies {
+		if strings.Contains(log, cookie) {
+			t.Errorf("expected the session cookie %s to be redacted from %s", cookie, log)
+		}
+	}
+	for _, want := range []string{`JSESSIONID=[REDACTED]`, `"[REDACTED]"`} {
+		if !strings.Contains(log, want) {
+			t.Errorf("expected %s in %s", want, log)
+		}
+	}
+}
+
+func TestLoggingRedactsSecrets(t *testing.T) {
+	srv := newDirector()
+	defer srv.Close()
+	srv.AddUser("auditor", "correct-horse-battery")
+
+	var (
+		buf     bytes.Buffer
+		cookies []string
+	)
+	client := loggingClient(t, srv, &buf, &cookies)
+	ctx := context.Background()
+	if _, _, err := client.Authentication.Login(ctx, "auditor", "correct-horse-battery", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.Job.JobInfo(ctx, []int{10}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(buf.String(), "nakivo exchange") != 2 {
+		t.Fatalf("expected 2 exchanges to be logged, got %s", buf.String())
+	}
+	assertRedacted(t, buf.String(), "correct-horse-battery", cookies)
+}
+
+func TestLoggingRedactsSecretsOfBatches(t *testing.T) {
+	srv := newDirector()
+	defer srv.Close()
+	srv.AddUser("auditor", "correct-horse-battery")
+
+	var (
+		buf     bytes.Buffer
+		cookies []string
+	)
+	client := loggingClient(t, srv, &buf, &cookies)
+	ctx := context.Background()
+
+	batch := client.NewBatch()
+	login := batch.Add(nakivo.AuthenticationAction, "login", []interface{}{"auditor", "correct-horse-battery", false}, nil)
+	batch.Add(nakivo.AuthenticationAction, "isLogged", nil, nil)
+	if _, err := batch.Do(ctx); err != nil || login.Err != nil {
+		t.Fatalf("unexpected errors %v and %v", err, login.Err)
+	}
+	batch = client.NewBatch()
+	batch.Add(nakivo.JobAction, "getJobInfo", []interface{}{[]int{10}, 0}, nil)
+	batch.Add(nakivo.AuthenticationAction, "isLogged", nil, nil)
+	if _, err := batch.Do(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(buf.String(), `"batch":2`) != 2 {
+		t.Fatalf("expected 2 batches to be logged, got %s", buf.String())
+	}
+	assertRedacted(t, buf.String(), "correct-horse-battery", cookies)
+}
@@ -0,0 +1,81 @@
+package nakivo
+
+import (
+	"context"
+	"net/http"
+)
+
+// Exchange is a single HTTP exchange with the router, carrying one request or a batch of
+// requests.
+type Exchange struct {
+	// Requests sent with the exchange. Holds more than one request for batches. The body of
+	// HTTPRequest is already encoded, so changes to the requests are not sent.
+	Requests []*Request
+
+	// Responses the replies are decoded into, in the order of Requests. Filled once the
+	// exchange completed.
+	Responses []*Response
+
+	// HTTPRequest sent to the router. Middleware may add headers before passing it on.
+	HTTPRequest *http.Request
+
+	// HTTPResponse received from the router. nil until the exchange completed or if the
+	// request failed on the transport. Its body is already consumed.
+	HTTPResponse *http.Response
+
+	// calls of a batch, nil for single requests
+	calls []*BatchCall
+}
+
+// Batch reports whether the exchange carries a batch of requests.
+func (ex *Exchange) Batch() bool {
+	return ex.calls != nil
+}
+
+// CallErr returns the error of the i-th request of a batch once the exchange completed, e.g. the
+// error reported by the director for the request, a missing response or a rejected login. nil
+// for single requests, whose error is returned by the Handler.
+func (ex *Exchange) CallErr(i int) error {
+	if ex.calls == nil {
+		return nil
+	}
+	return ex.calls[i].Err
+}
+
+// Handler performs an exchange. For batches, the returned error is only set if the batch as a
+// whole failed.
+type Handler func(ctx context.Context, ex *Exchange) error
+
+// Middleware wraps a Handler to observe or modify exchanges, e.g. for logging, auditing,
+// metrics or fault injection. It is invoked for every attempt, including retries and logins.
+type Middleware func(next Handler) Handler
+
+// WithMiddleware adds middleware to the client. The first middleware is the outermost one, i.e.
+// it sees an exchange first and its result last.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *Client) error {
+		c.middleware = append(c.middleware, middleware...)
+		return nil
+	}
+}
+
+// chain wraps h with middleware.
+func chain(h Handler, middleware []Middleware) Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		h = middleware[i](h)
+	}
+	return h
+}
+
+// roundTrip is the innermost handler which actually sends the exchange.
+func (c *Client) roundTrip(ctx context.Context, ex *Exchange) error {
+	if ex.Batch() {
+		return c.roundTripBatch(ctx, ex)
+	}
+	resp, err := c.receive(ctx, ex.HTTPRequest, ex.Responses[0])
+	ex.HTTPResponse = resp
+	if err == nil && isLoginRequest(ex.Requests[0]) {
+		err = loginError(ex.Requests[0], ex.Responses[0], resp)
+	}
+	return err
+}
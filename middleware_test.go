@@ -0,0 +1,48 @@
+package nakivo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/peertechde/go-nakivo"
+	"github.com/peertechde/go-nakivo/nakivotest"
+)
+
+func TestMiddlewareObservesRejectedLogin(t *testing.T) {
+	srv := nakivotest.NewServer()
+	defer srv.Close()
+	srv.AddUser("admin", "secret")
+
+	var observed []error
+	observe := func(next nakivo.Handler) nakivo.Handler {
+		return func(ctx context.Context, ex *nakivo.Exchange) error {
+			err := next(ctx, ex)
+			observed = append(observed, err)
+			return err
+		}
+	}
+	client, err := srv.Client(nakivo.WithMiddleware(observe))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loginInfo, _, err := client.Authentication.Login(context.Background(), "admin", "wrong", false)
+	if !errors.Is(err, nakivo.ErrAuthenticationRequired) {
+		t.Fatalf("expected ErrAuthenticationRequired, got %v", err)
+	}
+	if loginInfo == nil || loginInfo.Result == nakivo.LoginResultOK {
+		t.Fatalf("expected the rejected login info, got %+v", loginInfo)
+	}
+	if len(observed) != 1 || !errors.Is(observed[0], nakivo.ErrAuthenticationRequired) {
+		t.Fatalf("expected the middleware to observe the rejected login, got %v", observed)
+	}
+
+	observed = nil
+	if _, _, err := client.Authentication.Login(context.Background(), "admin", "secret", false); err != nil {
+		t.Fatal(err)
+	}
+	if len(observed) != 1 || observed[0] != nil {
+		t.Fatalf("expected the middleware to observe a successful login, got %v", observed)
+	}
+}
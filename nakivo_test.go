@@ -0,0 +1,105 @@
+package nakivo_test
+
+import (
+	"encoding/json"
+	"flag"
+	"path/filepath"
+	"testing"
+
+	"github.com/peertechde/go-nakivo"
+	"github.com/peertechde/go-nakivo/nakivotest"
+)
+
+// record records the fixtures in testdata against the fake director returned by newDirector
+// instead of replaying them.
+var record = flag.Bool("record", false, "record the fixtures in testdata")
+
+// replayURL is the router URL of clients replaying fixtures. It is never dialed.
+const replayURL = "https://director.invalid:4443/c/router"
+
+// fixtureClient returns a client replaying the exchanges of the fixture testdata/<name>.json.
+func fixtureClient(t *testing.T, name string) *nakivo.Client {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".json")
+	mode, baseURL := nakivotest.ModeReplay, replayURL
+	if *record {
+		srv := newDirector()
+		t.Cleanup(srv.Close)
+		mode, baseURL = nakivotest.ModeRecord, srv.URL()
+	}
+	rec, err := nakivotest.NewRecorder(path, mode, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := rec.Save(); err != nil {
+			t.Error(err)
+		}
+	})
+	client, err := nakivo.NewClient(rec.Client(), "", 0, nakivo.WithBaseURL(baseURL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// newDirector returns a fake director with the user admin and a small inventory of job groups
+// and jobs.
+func newDirector() *nakivotest.Server {
+	srv := nakivotest.NewServer()
+	srv.AddUser("admin", "secret")
+
+	srv.AddGroup(nakivo.Group{Id: 1, Vid: "JobGroup-1", Name: "Production", IsEnabled: true}, 0)
+	srv.AddGroup(nakivo.Group{Id: 2, Vid: "JobGroup-2", Name: "Databases", IsEnabled: true}, 1)
+	srv.AddJob(nakivo.Job{
+		Id:                10,
+		Vid:               "Job-10",
+		Name:              "Web servers",
+		JobType:           nakivo.JobTypeBackup,
+		IsEnabled:         true,
+		IsLicensed:        true,
+		VmCount:           3,
+		DiskCount:         4,
+		HasLastRun:        true,
+		LrState:           nakivo.JobStateOK,
+		LrDate:            "2024-03-04T22:00:00.000Z",
+		LrFinishDate:      "2024-03-04T22:40:00.000Z",
+		AverageDurationMs: 45 * 60 * 1000,
+		Schedules: []nakivo.Schedule{{
+			Enabled:   true,
+			Type:      nakivo.ScheduleTypeDaily,
+			StartTime: "10:00:00 PM",
+			Timezone:  "UTC",
+			On:        int(nakivo.Weekdays),
+			NextRun:   "2024-03-05T22:00:00.000Z",
+		}},
+	}, 1)
+	srv.AddJob(nakivo.Job{
+		Id:         11,
+		Vid:        "Job-11",
+		Name:       "Postgres",
+		JobType:    nakivo.JobTypeReplication,
+		IsEnabled:  true,
+		IsLicensed: true,
+		VmCount:    1,
+		DiskCount:  2,
+		Schedules: []nakivo.Schedule{{
+			Enabled:         true,
+			Type:            nakivo.ScheduleTypeTrigger,
+			TriggerItem:     "Job-10",
+			TriggerItemName: "Web servers",
+			TriggerRunType:  nakivo.TriggerRunTypeImmediately,
+			TriggerEvents:   []nakivo.TriggerEvent{nakivo.TriggerEventRunSuccess},
+		}},
+	}, 2)
+	return srv
+}
+
+// requestParams returns the positional parameters of request.
+func requestParams(request *nakivo.Request) []json.RawMessage {
+	var params []json.RawMessage
+	b, _ := json.Marshal(request.Data)
+	json.Unmarshal(b, &params)
+	return params
+}
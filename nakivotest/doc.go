@@ -0,0 +1,3 @@
+// Package nakivotest provides helpers to test code using the nakivo client without access to
+// a real director.
+package nakivotest
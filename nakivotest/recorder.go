@@ -0,0 +1,435 @@
+package nakivotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/peertechde/go-nakivo"
+)
+
+// Mode selects whether a Recorder records or replays exchanges.
+type Mode int
+
+const (
+	// ModeReplay serves the exchanges from the fixture file, without any network access.
+	ModeReplay Mode = iota
+
+	// ModeRecord passes the requests to the real director and records the exchanges, which are
+	// written to the fixture file by Save.
+	ModeRecord
+)
+
+// scrubbed replaces credentials and session cookies in fixtures.
+const scrubbed = "SCRUBBED"
+
+// Interaction is a recorded exchange with the router.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	// Method of the HTTP request
+	Method string `json:"method"`
+
+	// Path of the HTTP request
+	Path string `json:"path"`
+
+	// Body is the request or batch of requests, with credentials scrubbed.
+	Body json.RawMessage `json:"body"`
+}
+
+type RecordedResponse struct {
+	// Status code of the HTTP response
+	StatusCode int `json:"statusCode"`
+
+	// Header of the HTTP response, with session cookies scrubbed
+	Header http.Header `json:"header,omitempty"`
+
+	// Body of the response if it is JSON
+	Body json.RawMessage `json:"body,omitempty"`
+
+	// Body of the response if it isn't JSON, e.g. an HTML error page
+	Text string `json:"text,omitempty"`
+}
+
+type fixture struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Recorder is an http.RoundTripper which records the exchanges with a real director into a
+// fixture file and replays them later, so code using the client can be tested offline.
+//
+// Requests are matched by their action, method and data; transaction ids are ignored while
+// matching and rewritten in replayed responses. Login usernames and passwords, the name and
+// email of the logged in user and session cookies are scrubbed from the fixtures.
+type Recorder struct {
+	mode      Mode
+	path      string
+	transport http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	used         []bool
+}
+
+// NewRecorder returns a recorder for the fixture file at path. In ModeRecord the requests are
+// sent through transport, http.DefaultTransport if nil. In ModeReplay the fixture file is
+// loaded and must exist.
+func NewRecorder(path string, mode Mode, transport http.RoundTripper) (*Recorder, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	r := &Recorder{
+		mode:      mode,
+		path:      path,
+		transport: transport,
+	}
+	if mode == ModeReplay {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read fixture failed (%s)", err)
+		}
+		var f fixture
+		if err := json.Unmarshal(b, &f); err != nil {
+			return nil, fmt.Errorf("decode fixture failed (%s)", err)
+		}
+		r.interactions = f.Interactions
+		r.used = make([]bool, len(f.Interactions))
+	}
+	return r, nil
+}
+
+// Client returns an http.Client using the recorder, to be passed to nakivo.NewClient.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Interactions returns the recorded or loaded interactions.
+func (r *Recorder) Interactions() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*Interaction(nil), r.interactions...)
+}
+
+// Save writes the recorded interactions to the fixture file. It is a no-op in ModeReplay.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	b, err := json.MarshalIndent(fixture{Interactions: r.interactions}, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("create fixture directory failed (%s)", err)
+	}
+	return os.WriteFile(r.path, append(b, '\n'), 0o644)
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	requests, err := decodeRequests(body)
+	if err != nil {
+		return nil, fmt.Errorf("nakivotest: decode request failed (%s)", err)
+	}
+	if r.mode == ModeReplay {
+		return r.replay(req, requests)
+	}
+	return r.record(req, body, requests)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte, requests []*nakivo.Request) (*http.Response, error) {
+	var stored interface{} = scrubRequests(requests, false)
+	if b := bytes.TrimSpace(body); len(b) == 0 || b[0] != '[' {
+		stored = scrubRequests(requests, false)[0]
+	}
+	recordedBody, err := json.Marshal(stored)
+	if err != nil {
+		return nil, err
+	}
+
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.ContentLength = int64(len(body))
+
+	resp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	recorded := RecordedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     scrubHeader(resp.Header),
+	}
+	if json.Valid(respBody) {
+		recorded.Body = scrubResponse(respBody, requests)
+	} else {
+		recorded.Text = string(respBody)
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, &Interaction{
+		Request:  RecordedRequest{Method: req.Method, Path: req.URL.Path, Body: recordedBody},
+		Response: recorded,
+	})
+	r.mu.Unlock()
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, requests []*nakivo.Request) (*http.Response, error) {
+	key, err := matchKey(requests)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// prefer the first unused interaction, but allow to replay the last one again, e.g. when
+	// the code under test polls
+	match := -1
+	for i, interaction := range r.interactions {
+		if interaction.Request.Method != req.Method {
+			continue
+		}
+		recorded, err := decodeRequests(interaction.Request.Body)
+		if err != nil {
+			continue
+		}
+		if k, err := matchKey(recorded); err != nil || k != key {
+			continue
+		}
+		match = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("nakivotest: no recorded interaction for %s", describe(requests))
+	}
+	r.used[match] = true
+	interaction := r.interactions[match]
+
+	body := []byte(interaction.Response.Text)
+	if len(interaction.Response.Body) > 0 {
+		var err error
+		body, err = rewriteTids(interaction.Request.Body, interaction.Response.Body, requests)
+		if err != nil {
+			return nil, fmt.Errorf("nakivotest: rewrite tids failed (%s)", err)
+		}
+	}
+	header := interaction.Response.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// decodeRequests decodes a single request or a batch of requests.
+func decodeRequests(body []byte) ([]*nakivo.Request, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var requests []*nakivo.Request
+		err := json.Unmarshal(body, &requests)
+		return requests, err
+	}
+	var request nakivo.Request
+	if err := json.Unmarshal(body, &request); err != nil {
+		return nil, err
+	}
+	return []*nakivo.Request{&request}, nil
+}
+
+// scrubRequests returns the requests with credentials replaced and, if dropTid is set,
+// transaction ids zeroed, as stored in fixtures and used to match requests.
+func scrubRequests(requests []*nakivo.Request, dropTid bool) []*nakivo.Request {
+	out := make([]*nakivo.Request, 0, len(requests))
+	for _, request := range requests {
+		r := *request
+		if dropTid {
+			r.Tid = 0
+		}
+		if isLogin(&r) {
+			// the username and password are the first two parameters
+			if params, ok := r.Data.([]interface{}); ok {
+				params = append([]interface{}(nil), params...)
+				for i := 0; i < len(params) && i < 2; i++ {
+					params[i] = scrubbed
+				}
+				r.Data = params
+			}
+		}
+		out = append(out, &r)
+	}
+	return out
+}
+
+// scrubResponse returns the response body with the name and email of the user replaced in
+// replies to logins. Other bodies are returned as is.
+func scrubResponse(body []byte, requests []*nakivo.Request) []byte {
+	login := false
+	for _, request := range requests {
+		login = login || isLogin(request)
+	}
+	if !login {
+		return body
+	}
+
+	scrub := func(raw json.RawMessage) json.RawMessage {
+		var m map[string]interface{}
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return raw
+		}
+		data, _ := m["data"].(map[string]interface{})
+		userInfo, _ := data["userInfo"].(map[string]interface{})
+		if userInfo == nil {
+			return raw
+		}
+		for _, key := range []string{"name", "email"} {
+			if v, ok := userInfo[key].(string); ok && v != "" {
+				userInfo[key] = scrubbed
+			}
+		}
+		b, err := json.Marshal(m)
+		if err != nil {
+			return raw
+		}
+		return b
+	}
+
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		return scrub(body)
+	}
+	var responses []json.RawMessage
+	if err := json.Unmarshal(trimmed, &responses); err != nil {
+		return body
+	}
+	for i := range responses {
+		responses[i] = scrub(responses[i])
+	}
+	b, err := json.Marshal(responses)
+	if err != nil {
+		return body
+	}
+	return b
+}
+
+func isLogin(request *nakivo.Request) bool {
+	return request.Action == nakivo.AuthenticationAction && request.Method == "login"
+}
+
+// scrubHeader returns header without volatile fields and with cookie values replaced.
+func scrubHeader(header http.Header) http.Header {
+	h := header.Clone()
+	h.Del("Date")
+	h.Del("Content-Length")
+	for i, v := range h.Values("Set-Cookie") {
+		name, rest, _ := strings.Cut(v, "=")
+		if _, attrs, ok := strings.Cut(rest, ";"); ok {
+			h["Set-Cookie"][i] = name + "=" + scrubbed + ";" + attrs
+		} else {
+			h["Set-Cookie"][i] = name + "=" + scrubbed
+		}
+	}
+	return h
+}
+
+// rewriteTids replaces the transaction ids of the recorded requests in the recorded response
+// with the ids of the replayed requests, matched by position.
+func rewriteTids(recorded, response json.RawMessage, requests []*nakivo.Request) ([]byte, error) {
+	recordedRequests, err := decodeRequests(recorded)
+	if err != nil {
+		return nil, err
+	}
+	tids := make(map[string]int, len(requests))
+	for i, request := range recordedRequests {
+		if i < len(requests) {
+			tids[fmt.Sprint(request.Tid)] = requests[i].Tid
+		}
+	}
+
+	rewrite := func(raw json.RawMessage) (json.RawMessage, error) {
+		var m map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return raw, nil
+		}
+		var tid interface{}
+		if err := json.Unmarshal(m["tid"], &tid); err != nil {
+			return raw, nil
+		}
+		replacement, ok := tids[fmt.Sprint(tid)]
+		if !ok {
+			return raw, nil
+		}
+		if _, isString := tid.(string); isString {
+			m["tid"], _ = json.Marshal(fmt.Sprint(replacement))
+		} else {
+			m["tid"], _ = json.Marshal(replacement)
+		}
+		return json.Marshal(m)
+	}
+
+	response = bytes.TrimSpace(response)
+	if len(response) == 0 || response[0] != '[' {
+		return rewrite(response)
+	}
+	var responses []json.RawMessage
+	if err := json.Unmarshal(response, &responses); err != nil {
+		return nil, err
+	}
+	for i, raw := range responses {
+		if responses[i], err = rewrite(raw); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(responses)
+}
+
+// matchKey returns the key requests are matched by: their action, method and scrubbed data.
+func matchKey(requests []*nakivo.Request) (string, error) {
+	b, err := json.Marshal(scrubRequests(requests, true))
+	return string(b), err
+}
+
+func describe(requests []*nakivo.Request) string {
+	names := make([]string, 0, len(requests))
+	for _, request := range requests {
+		names = append(names, request.Action+"."+request.Method)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,81 @@
+package nakivotest_test
+
+import (
+	"context"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/peertechde/go-nakivo"
+	"github.com/peertechde/go-nakivo/nakivotest"
+)
+
+func TestRecorderScrubsCredentials(t *testing.T) {
+	srv := nakivotest.NewServer()
+	defer srv.Close()
+	srv.AddUser("operator", "hunter2")
+
+	path := filepath.Join(t.TempDir(), "login.json")
+	rec, err := nakivotest.NewRecorder(path, nakivotest.ModeRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpClient := rec.Client()
+	if httpClient.Jar, err = cookiejar.New(nil); err != nil {
+		t.Fatal(err)
+	}
+	client, err := nakivo.NewClient(httpClient, "", 0, nakivo.WithBaseURL(srv.URL()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	loginInfo, _, err := client.Authentication.Login(context.Background(), "operator", "hunter2", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the client sees the reply as sent by the director
+	if loginInfo.UserInfo.Name != "operator" {
+		t.Fatalf("expected user operator, got %q", loginInfo.UserInfo.Name)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"operator", "hunter2"} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("fixture contains %q:\n%s", secret, b)
+		}
+	}
+	u, err := url.Parse(srv.URL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cookies := httpClient.Jar.Cookies(u)
+	if len(cookies) == 0 {
+		t.Fatal("expected a session cookie")
+	}
+	for _, c := range cookies {
+		if strings.Contains(string(b), c.Value) {
+			t.Errorf("fixture contains the session cookie:\n%s", b)
+		}
+	}
+
+	// the scrubbed fixture replays the login of any user
+	replay, err := nakivotest.NewRecorder(path, nakivotest.ModeReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err = nakivo.NewClient(replay.Client(), "", 0, nakivo.WithBaseURL(srv.URL()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.Authentication.Login(context.Background(), "someone", "else", false); err != nil {
+		t.Fatal(err)
+	}
+}
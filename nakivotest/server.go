@@ -0,0 +1,649 @@
+package nakivotest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/peertechde/go-nakivo"
+)
+
+// sessionCookie is the name of the cookie holding the session id.
+const sessionCookie = "JSESSIONID"
+
+// HandlerFunc handles a method of the fake director. params are the positional parameters of
+// the request. The returned data is sent as the data of the response; a non-nil *APIError is
+// reported as the error of the request instead.
+type HandlerFunc func(session *Session, params []json.RawMessage) (interface{}, *nakivo.APIError)
+
+// Session is an authenticated session of the fake director.
+type Session struct {
+	ID       string
+	Username string
+}
+
+// Server is an in-process fake director speaking the RPC protocol of the router. It implements
+// the login, isLogged and logoutCurrentUser methods of AuthenticationManagement, the
+// getGroupInfo and getJobInfo methods of JobSummaryManagement and the runJob and stopJob methods
+// of JobManagement against an in-memory model of job groups and jobs. Further methods can be
+// added with Handle.
+type Server struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	users    map[string]string
+	sessions map[string]*Session
+	groups   map[int]*group
+	jobs     map[int]*nakivo.Job
+	handlers map[string]map[string]HandlerFunc
+	injected []*injection
+
+	maxFailedAttempts int
+	lockoutDuration   time.Duration
+	failedAttempts    int
+	lockedUntil       time.Time
+}
+
+type group struct {
+	nakivo.Group
+	parent int
+	jobs   []int
+}
+
+// injection is an error injected into the responses of the server.
+type injection struct {
+	action, method string
+	err            *nakivo.APIError
+	status         int
+	times          int
+}
+
+// NewServer starts a fake director. It must be closed by calling Close.
+func NewServer() *Server {
+	s := &Server{
+		users:             make(map[string]string),
+		sessions:          make(map[string]*Session),
+		groups:            make(map[int]*group),
+		jobs:              make(map[int]*nakivo.Job),
+		handlers:          make(map[string]map[string]HandlerFunc),
+		maxFailedAttempts: 5,
+		lockoutDuration:   time.Minute,
+	}
+	s.Handle(nakivo.AuthenticationAction, "isLogged", s.isLogged)
+	s.Handle(nakivo.AuthenticationAction, "logoutCurrentUser", s.logout)
+	s.Handle(nakivo.JobAction, "getGroupInfo", s.getGroupInfo)
+	s.Handle(nakivo.JobAction, "getJobInfo", s.getJobInfo)
+	s.Handle(nakivo.JobManagementAction, "runJob", s.runJob)
+	s.Handle(nakivo.JobManagementAction, "stopJob", s.stopJob)
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// URL returns the URL of the router of the fake director.
+func (s *Server) URL() string {
+	return s.server.URL + "/c/router"
+}
+
+// Client returns a client talking to the fake director.
+func (s *Server) Client(opts ...nakivo.Option) (*nakivo.Client, error) {
+	opts = append([]nakivo.Option{nakivo.WithBaseURL(s.URL())}, opts...)
+	return nakivo.NewClient(nil, "", 0, opts...)
+}
+
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Handle registers handler for method of action. Handlers of the fake director itself may be
+// replaced. All methods except login require an authenticated session.
+func (s *Server) Handle(action, method string, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.handlers[action] == nil {
+		s.handlers[action] = make(map[string]HandlerFunc)
+	}
+	s.handlers[action][method] = handler
+}
+
+// AddUser adds a user which can log in.
+func (s *Server) AddUser(username, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[username] = password
+}
+
+// SetLockout locks logins out for duration after attempts failed logins in a row.
+func (s *Server) SetLockout(attempts int, duration time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxFailedAttempts = attempts
+	s.lockoutDuration = duration
+}
+
+// ExpireSessions invalidates all sessions, as a restart of the director does.
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions = make(map[string]*Session)
+}
+
+// AddGroup adds a job group as a child of the group parent, 0 for a top level group. The
+// derived fields, e.g. the child ids and counts, are computed by the server.
+func (s *Server) AddGroup(g nakivo.Group, parent int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.groups[g.Id] = &group{Group: g, parent: parent}
+}
+
+// AddJob adds a job to the group with id groupID.
+func (s *Server) AddJob(job nakivo.Job, groupID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j := job
+	s.jobs[job.Id] = &j
+	if g, ok := s.groups[groupID]; ok {
+		g.jobs = append(g.jobs, job.Id)
+	}
+}
+
+// UpdateJob replaces the job with the same id, keeping its group.
+func (s *Server) UpdateJob(job nakivo.Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j := job
+	s.jobs[job.Id] = &j
+}
+
+// Job returns a copy of the job with id, if it exists.
+func (s *Server) Job(id int) (nakivo.Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return nakivo.Job{}, false
+	}
+	return *j, true
+}
+
+// MoveJob moves the job with id to the group with id groupID.
+func (s *Server) MoveJob(id, groupID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, g := range s.groups {
+		g.jobs = removeID(g.jobs, id)
+	}
+	if g, ok := s.groups[groupID]; ok {
+		g.jobs = append(g.jobs, id)
+	}
+}
+
+// RemoveJob removes the job with id.
+func (s *Server) RemoveJob(id int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.jobs, id)
+	for _, g := range s.groups {
+		g.jobs = removeID(g.jobs, id)
+	}
+}
+
+// InjectError makes the next times requests of method of action fail with err. An empty action
+// or method matches any.
+func (s *Server) InjectError(action, method string, err *nakivo.APIError, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.injected = append(s.injected, &injection{action: action, method: method, err: err, times: times})
+}
+
+// InjectHTTPError makes the next times exchanges containing a request of method of action fail
+// with the HTTP status and a non-JSON body. An empty action or method matches any.
+func (s *Server) InjectHTTPError(action, method string, status, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.injected = append(s.injected, &injection{action: action, method: method, status: status, times: times})
+}
+
+type response struct {
+	Action  string      `json:"action"`
+	Method  string      `json:"method"`
+	Tid     string      `json:"tid"`
+	Type    string      `json:"type"`
+	Message string      `json:"message,omitempty"`
+	Where   string      `json:"where,omitempty"`
+	Cause   string      `json:"cause,omitempty"`
+	Data    interface{} `json:"data"`
+}
+
+type request struct {
+	Action string            `json:"action"`
+	Method string            `json:"method"`
+	Tid    json.RawMessage   `json:"tid"`
+	Data   []json.RawMessage `json:"data"`
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/c/router" {
+		http.NotFound(w, r)
+		return
+	}
+	var raw json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	batch := len(bytes.TrimSpace(raw)) > 0 && bytes.TrimSpace(raw)[0] == '['
+	var requests []request
+	if batch {
+		if err := json.Unmarshal(raw, &requests); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else {
+		var req request
+		if err := json.Unmarshal(raw, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		requests = []request{req}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, req := range requests {
+		if inj := s.take(req, true); inj != nil {
+			http.Error(w, http.StatusText(inj.status), inj.status)
+			return
+		}
+	}
+
+	var session *Session
+	if c, err := r.Cookie(sessionCookie); err == nil {
+		session = s.sessions[c.Value]
+	}
+
+	responses := make([]response, 0, len(requests))
+	for _, req := range requests {
+		resp := response{Action: req.Action, Method: req.Method, Tid: tid(req.Tid), Type: "rpc"}
+		var data interface{}
+		var apiErr *nakivo.APIError
+		if inj := s.take(req, false); inj != nil {
+			apiErr = inj.err
+		} else if req.Action == nakivo.AuthenticationAction && req.Method == "login" {
+			data, session = s.login(w, req.Data)
+		} else {
+			data, apiErr = s.dispatch(session, req)
+		}
+		if apiErr != nil {
+			resp.Message, resp.Where, resp.Cause = apiErr.Message, apiErr.Where, apiErr.Cause
+		} else {
+			resp.Data = data
+		}
+		responses = append(responses, resp)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if batch {
+		json.NewEncoder(w).Encode(responses)
+	} else {
+		json.NewEncoder(w).Encode(responses[0])
+	}
+}
+
+func (s *Server) dispatch(session *Session, req request) (interface{}, *nakivo.APIError) {
+	handler, ok := s.handlers[req.Action][req.Method]
+	if !ok {
+		return nil, &nakivo.APIError{Message: "Unknown method", Where: req.Action + "." + req.Method, Cause: "NoSuchMethodException"}
+	}
+	if session == nil {
+		return nil, &nakivo.APIError{Message: "Not logged in", Cause: "NotAuthenticatedException"}
+	}
+	return handler(session, req.Data)
+}
+
+// take returns the injected error matching req, of the HTTP or the API kind, and counts it as
+// used.
+func (s *Server) take(req request, http bool) *injection {
+	for _, inj := range s.injected {
+		if inj.times <= 0 || (inj.status != 0) != http {
+			continue
+		}
+		if (inj.action == "" || inj.action == req.Action) && (inj.method == "" || inj.method == req.Method) {
+			inj.times--
+			return inj
+		}
+	}
+	return nil
+}
+
+func (s *Server) login(w http.ResponseWriter, params []json.RawMessage) (*nakivo.LoginInfo, *Session) {
+	var username, password string
+	if len(params) > 1 {
+		json.Unmarshal(params[0], &username)
+		json.Unmarshal(params[1], &password)
+	}
+
+	info := &nakivo.LoginInfo{CanTry: nakivo.CanTry{IsPossible: true}}
+	if wait := time.Until(s.lockedUntil); wait > 0 {
+		info.Result, info.Reason = "FAILED", "LOCKED"
+		info.CanTry = nakivo.CanTry{WaitTimeLeft: int(wait / time.Millisecond), FailedAttempts: s.failedAttempts}
+		return info, nil
+	}
+	if pw, ok := s.users[username]; !ok || pw != password {
+		s.failedAttempts++
+		info.Result, info.Reason = "FAILED", "INVALID_CREDENTIALS"
+		info.CanTry.FailedAttempts = s.failedAttempts
+		if s.maxFailedAttempts > 0 && s.failedAttempts >= s.maxFailedAttempts {
+			s.lockedUntil = time.Now().Add(s.lockoutDuration)
+			info.CanTry.IsPossible = false
+			info.CanTry.WaitTimeLeft = int(s.lockoutDuration / time.Millisecond)
+		}
+		return info, nil
+	}
+	s.failedAttempts = 0
+
+	session := &Session{ID: newSessionID(), Username: username}
+	s.sessions[session.ID] = session
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: session.ID, Path: "/", HttpOnly: true})
+
+	info.Result = nakivo.LoginResultOK
+	info.ProductConfigured = true
+	info.UserInfo = nakivo.UserInfo{Name: username, IsAdmin: true}
+	return info, session
+}
+
+func (s *Server) isLogged(session *Session, params []json.RawMessage) (interface{}, *nakivo.APIError) {
+	return true, nil
+}
+
+func (s *Server) logout(session *Session, params []json.RawMessage) (interface{}, *nakivo.APIError) {
+	delete(s.sessions, session.ID)
+	return nil, nil
+}
+
+func (s *Server) getGroupInfo(session *Session, params []json.RawMessage) (interface{}, *nakivo.APIError) {
+	var ids []*int
+	var collectAll bool
+	if len(params) > 0 {
+		if err := json.Unmarshal(params[0], &ids); err != nil {
+			return nil, invalidParams(err)
+		}
+	}
+	if len(params) > 2 {
+		json.Unmarshal(params[2], &collectAll)
+	}
+
+	var selected []int
+	if len(ids) == 0 || (len(ids) == 1 && ids[0] == nil) {
+		for id := range s.groups {
+			selected = append(selected, id)
+		}
+		sort.Ints(selected)
+	} else {
+		for _, id := range ids {
+			if id == nil {
+				continue
+			}
+			if _, ok := s.groups[*id]; !ok {
+				return nil, &nakivo.APIError{Message: fmt.Sprintf("Job group %d not found", *id), Cause: "ItemNotFoundException"}
+			}
+			selected = append(selected, *id)
+		}
+	}
+
+	groups := nakivo.Groups{Children: []nakivo.Group{}}
+	for _, id := range selected {
+		groups.Children = append(groups.Children, s.groupInfo(id, collectAll))
+	}
+	return groups, nil
+}
+
+// groupInfo returns the group with id with the derived fields computed from the model.
+func (s *Server) groupInfo(id int, collectAll bool) nakivo.Group {
+	g := s.groups[id].Group
+
+	var children []int
+	for childID, child := range s.groups {
+		if child.parent == id {
+			children = append(children, childID)
+		}
+	}
+	sort.Ints(children)
+	g.ImmediateChildJobIds = append(children, s.groups[id].jobs...)
+
+	jobs := s.groupJobs(id, collectAll)
+	g.ChildJobIds = jobs
+	g.JobCount = nakivo.Group{}.JobCount
+	g.JobCountEnabled, g.JobCountLicensed, g.VMCount, g.DiskCount, g.SourcesSize = 0, 0, 0, 0, 0
+	g.CrJobRunning, g.CrVMRunning, g.LrJobOk, g.LrJobFailed, g.LrJobStopped = 0, 0, 0, 0, 0
+	g.HasLastRun = false
+	for _, jobID := range jobs {
+		job := s.jobs[jobID]
+		if job.IsEnabled {
+			g.JobCountEnabled++
+		}
+		if job.IsLicensed {
+			g.JobCountLicensed++
+		}
+		g.VMCount += job.VmCount
+		g.DiskCount += job.DiskCount
+		g.SourcesSize += int(job.SourcesSize)
+		if job.CrState.IsRunning() {
+			g.CrJobRunning++
+			g.CrVMRunning += job.CrVmPlanned
+		}
+		if job.HasLastRun {
+			g.HasLastRun = true
+			switch job.LrState {
+			case nakivo.JobStateOK:
+				g.LrJobOk++
+			case nakivo.JobStateFailed:
+				g.LrJobFailed++
+			case nakivo.JobStateStopped:
+				g.LrJobStopped++
+			}
+		}
+		switch job.JobType {
+		case nakivo.JobTypeReplication:
+			g.JobCount.Replication++
+		case nakivo.JobTypeBackup:
+			g.JobCount.Backup++
+		case nakivo.JobTypeRecoveryVMs:
+			g.JobCount.RecoveryVMs++
+		case nakivo.JobTypeRecoveryFiles:
+			g.JobCount.RecoveryFiles++
+		case nakivo.JobTypeRecoveryBackups:
+			g.JobCount.RecoveryBackups++
+		case nakivo.JobTypeBackupCopy:
+			g.JobCount.BackupCopy++
+		case nakivo.JobTypeFlashBoot:
+			g.JobCount.FlashBoot++
+		}
+	}
+	return g
+}
+
+// groupJobs returns the ids of the jobs of the group with id, including the jobs of all sub
+// groups if recursive is set.
+func (s *Server) groupJobs(id int, recursive bool) []int {
+	var jobs []int
+	for _, jobID := range s.groups[id].jobs {
+		if _, ok := s.jobs[jobID]; ok {
+			jobs = append(jobs, jobID)
+		}
+	}
+	if recursive {
+		var children []int
+		for childID, child := range s.groups {
+			if child.parent == id {
+				children = append(children, childID)
+			}
+		}
+		sort.Ints(children)
+		for _, childID := range children {
+			jobs = append(jobs, s.groupJobs(childID, true)...)
+		}
+	}
+	return jobs
+}
+
+func (s *Server) getJobInfo(session *Session, params []json.RawMessage) (interface{}, *nakivo.APIError) {
+	var ids []int
+	if len(params) > 0 {
+		if err := json.Unmarshal(params[0], &ids); err != nil {
+			return nil, invalidParams(err)
+		}
+	}
+	jobs := nakivo.Jobs{Children: []nakivo.Job{}}
+	for _, id := range ids {
+		if job, ok := s.jobs[id]; ok {
+			jobs.Children = append(jobs.Children, *job)
+		}
+	}
+	return jobs, nil
+}
+
+type jobParams struct {
+	JobIds     []int    `json:"jobId"`
+	ObjectVids []string `json:"objectVids"`
+}
+
+func (s *Server) jobParams(params []json.RawMessage) ([]*nakivo.Job, []string, *nakivo.APIError) {
+	var p jobParams
+	if len(params) > 0 {
+		if err := json.Unmarshal(params[0], &p); err != nil {
+			return nil, nil, invalidParams(err)
+		}
+	}
+	jobs := make([]*nakivo.Job, 0, len(p.JobIds))
+	for _, id := range p.JobIds {
+		job, ok := s.jobs[id]
+		if !ok {
+			return nil, nil, &nakivo.APIError{Message: fmt.Sprintf("Job %d not found", id), Cause: "ItemNotFoundException"}
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, p.ObjectVids, nil
+}
+
+// runJob starts the jobs. The runs last until they are finished with FinishJob or stopped.
+func (s *Server) runJob(session *Session, params []json.RawMessage) (interface{}, *nakivo.APIError) {
+	jobs, vids, apiErr := s.jobParams(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	for _, job := range jobs {
+		if job.CrState.IsRunning() {
+			continue
+		}
+		planned := job.VmCount
+		if len(vids) > 0 {
+			planned = len(vids)
+		}
+		job.CrState = nakivo.JobStateRunning
+		job.CrDate = time.Now().UTC().Format(nakivo.TimeLayout)
+		job.CrAdhoc = true
+		job.CrProgress = 0
+		job.CrVmPlanned = planned
+		job.CrVmOk, job.CrVmFailed, job.CrVmStopped = 0, 0, 0
+	}
+	return nil, nil
+}
+
+func (s *Server) stopJob(session *Session, params []json.RawMessage) (interface{}, *nakivo.APIError) {
+	jobs, _, apiErr := s.jobParams(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	for _, job := range jobs {
+		if job.CrState.IsRunning() {
+			finish(job, nakivo.JobStateStopped)
+		}
+	}
+	return nil, nil
+}
+
+// SetProgress sets the progress of the current run of the job with id, as well as the number
+// of machines processed successfully and failed so far.
+func (s *Server) SetProgress(id, progress, vmOk, vmFailed int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if job, ok := s.jobs[id]; ok && job.CrState.IsRunning() {
+		job.CrProgress = progress
+		job.CrVmOk = vmOk
+		job.CrVmFailed = vmFailed
+	}
+}
+
+// FinishJob finishes the current run of the job with id with state, e.g. nakivo.JobStateOK or
+// nakivo.JobStateFailed.
+func (s *Server) FinishJob(id int, state nakivo.JobState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if job, ok := s.jobs[id]; ok && job.CrState.IsRunning() {
+		finish(job, state)
+	}
+}
+
+// finish moves the current run of job to its last run.
+func finish(job *nakivo.Job, state nakivo.JobState) {
+	now := time.Now().UTC()
+	started, err := time.Parse(time.RFC3339Nano, job.CrDate)
+	if err != nil {
+		started = now
+	}
+	job.HasLastRun = true
+	job.LrState = state
+	job.LrDate = job.CrDate
+	job.LrFinishDate = now.Format(nakivo.TimeLayout)
+	job.LrDurationMs = int64(now.Sub(started) / time.Millisecond)
+	job.LrAdhoc = job.CrAdhoc
+	job.LrVmOk = job.CrVmOk
+	job.LrVmFailed = job.CrVmFailed
+	job.LrVmStopped = job.CrVmPlanned - job.CrVmOk - job.CrVmFailed
+	if state != nakivo.JobStateStopped {
+		job.LrVmStopped = 0
+	}
+
+	job.CrState = nakivo.JobStateWaitingDemand
+	job.CrDate = ""
+	job.CrProgress = 0
+	job.CrAdhoc = false
+	job.CrVmPlanned, job.CrVmOk, job.CrVmFailed, job.CrVmStopped = 0, 0, 0, 0
+}
+
+func invalidParams(err error) *nakivo.APIError {
+	return &nakivo.APIError{Message: "Invalid parameters", Cause: err.Error()}
+}
+
+func tid(raw json.RawMessage) string {
+	if s, err := strconv.Unquote(string(raw)); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
+func newSessionID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func removeID(ids []int, id int) []int {
+	out := ids[:0]
+	for _, i := range ids {
+		if i != id {
+			out = append(out, i)
+		}
+	}
+	return out
+}
@@ -0,0 +1,103 @@
+package nakivo
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Option configures optional behavior of a Client created by NewClient.
+type Option func(*Client) error
+
+// WithRetryPolicy sets the policy used to retry requests failing with transient errors. By
+// default requests are not retried.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) error {
+		c.retryPolicy = policy
+		return nil
+	}
+}
+
+// WithBaseURL overrides the full URL of the router, e.g.
+// "https://proxy.example.com/nakivo/c/router" if the director is only reachable through a
+// reverse proxy. The address and port passed to NewClient are ignored.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) error {
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return fmt.Errorf("invalid base url (%s)", err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("invalid base url (unsupported scheme '%s')", u.Scheme)
+		}
+		if u.Host == "" {
+			return fmt.Errorf("invalid base url (missing host)")
+		}
+		c.baseURL = u
+		return nil
+	}
+}
+
+// WithPathPrefix prepends prefix to the path of the router, e.g. "/nakivo" if a reverse proxy
+// serves the director below a sub path.
+func WithPathPrefix(prefix string) Option {
+	return func(c *Client) error {
+		c.pathPrefix = prefix
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) error {
+		c.userAgent = userAgent
+		return nil
+	}
+}
+
+// WithHeader adds a header sent with every request. May be used several times.
+func WithHeader(key, value string) Option {
+	return func(c *Client) error {
+		c.header.Add(key, value)
+		return nil
+	}
+}
+
+// WithTimeout sets the timeout of every request whose context has no deadline.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		if timeout < 0 {
+			return fmt.Errorf("invalid timeout %s", timeout)
+		}
+		c.timeout = timeout
+		return nil
+	}
+}
+
+// WithProxy sends all requests through the proxy at proxyURL, e.g. "http://proxy:3128". The
+// schemes http, https and socks5 are supported.
+func WithProxy(proxyURL string) Option {
+	return func(c *Client) error {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy url (%s)", err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5" {
+			return fmt.Errorf("invalid proxy url (unsupported scheme '%s')", u.Scheme)
+		}
+		if u.Host == "" {
+			return fmt.Errorf("invalid proxy url (missing host)")
+		}
+		c.proxy = http.ProxyURL(u)
+		return nil
+	}
+}
+
+// WithProxyFunc selects the proxy of every request with proxy, see http.Transport.Proxy.
+func WithProxyFunc(proxy func(*http.Request) (*url.URL, error)) Option {
+	return func(c *Client) error {
+		c.proxy = proxy
+		return nil
+	}
+}
@@ -0,0 +1,136 @@
+package nakivo_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/peertechde/go-nakivo"
+)
+
+// received returns a director recording the requests it receives into requests.
+func received(t *testing.T, requests *[]*http.Request) *httptest.Server {
+	t.Helper()
+	answer := router(answerTrue)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r)
+		answer.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// callWith calls isLogged with a client configured with opts.
+func callWith(t *testing.T, opts ...nakivo.Option) {
+	t.Helper()
+	client, err := nakivo.NewClient(nil, "director.invalid", 4443, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.Authentication.IsLoggedIn(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithBaseURL(t *testing.T) {
+	for _, baseURL := range []string{"", "director:4443/c/router", "ftp://director/c/router", "https://"} {
+		if _, err := nakivo.NewClient(nil, "director", 4443, nakivo.WithBaseURL(baseURL)); err == nil {
+			t.Errorf("expected base url %q to be invalid", baseURL)
+		}
+	}
+}
+
+func TestWithPathPrefix(t *testing.T) {
+	var requests []*http.Request
+	srv := received(t, &requests)
+
+	for _, prefix := range []string{"nakivo", "/nakivo/"} {
+		callWith(t, nakivo.WithBaseURL(srv.URL+"/c/router"), nakivo.WithPathPrefix(prefix))
+	}
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+	for _, r := range requests {
+		if r.URL.Path != "/nakivo/c/router" {
+			t.Errorf("expected the path /nakivo/c/router, got %s", r.URL.Path)
+		}
+	}
+}
+
+func TestWithHeader(t *testing.T) {
+	var requests []*http.Request
+	srv := received(t, &requests)
+
+	callWith(t, nakivo.WithBaseURL(srv.URL))
+	callWith(t, nakivo.WithBaseURL(srv.URL), nakivo.WithUserAgent("backup-reports/1.0"),
+		nakivo.WithHeader("X-Tenant", "a"), nakivo.WithHeader("X-Tenant", "b"), nakivo.WithHeader("X-Request-Source", "cron"))
+
+	if ua := requests[0].Header.Get("User-Agent"); ua != "go-nakivo" {
+		t.Errorf("expected the default user agent, got %q", ua)
+	}
+	header := requests[1].Header
+	if ua := header.Get("User-Agent"); ua != "backup-reports/1.0" {
+		t.Errorf("expected the user agent backup-reports/1.0, got %q", ua)
+	}
+	if !reflect.DeepEqual(header.Values("X-Tenant"), []string{"a", "b"}) || header.Get("X-Request-Source") != "cron" {
+		t.Errorf("expected the configured headers, got %v", header)
+	}
+	if header.Get("Content-Type") != "application/json" {
+		t.Errorf("expected the content type application/json, got %q", header.Get("Content-Type"))
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	answer := router(answerTrue)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		answer.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	client, err := nakivo.NewClient(nil, "", 0, nakivo.WithBaseURL(srv.URL), nakivo.WithTimeout(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.Authentication.IsLoggedIn(context.Background()); err == nil {
+		t.Error("expected the request to time out")
+	}
+
+	// the deadline of the context takes precedence
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, _, err := client.Authentication.IsLoggedIn(ctx); err != nil {
+		t.Errorf("unexpected error (%s)", err)
+	}
+
+	if _, err := nakivo.NewClient(nil, "director", 4443, nakivo.WithTimeout(-time.Second)); err == nil {
+		t.Error("expected a negative timeout to be rejected")
+	}
+}
+
+func TestWithProxy(t *testing.T) {
+	var requests []*http.Request
+	proxy := received(t, &requests)
+
+	// the director is only reachable through the proxy
+	callWith(t, nakivo.WithBaseURL("http://director.invalid:4443/c/router"), nakivo.WithProxy(proxy.URL))
+	if len(requests) != 1 || requests[0].Host != "director.invalid:4443" {
+		t.Fatalf("expected the request to the director through the proxy, got %v", requests)
+	}
+
+	for _, proxyURL := range []string{"", "proxy:3128", "ftp://proxy:21", "http://", ":"} {
+		if _, err := nakivo.NewClient(nil, "director", 4443, nakivo.WithProxy(proxyURL)); err == nil {
+			t.Errorf("expected proxy url %q to be invalid", proxyURL)
+		}
+	}
+	if _, err := nakivo.NewClient(nil, "director", 4443, nakivo.WithProxy("socks5://proxy:1080")); err != nil {
+		t.Errorf("unexpected error (%s)", err)
+	}
+}
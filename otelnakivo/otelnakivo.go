@@ -0,0 +1,134 @@
+// Package otelnakivo traces the calls of a nakivo.Client with OpenTelemetry.
+//
+//	client, err := nakivo.NewClient(nil, address, port, nakivo.WithMiddleware(otelnakivo.Middleware()))
+package otelnakivo
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/peertechde/go-nakivo"
+)
+
+const instrumentationName = "github.com/peertechde/go-nakivo/otelnakivo"
+
+// Attribute keys recorded on the spans.
+const (
+	RPCSystemKey  = attribute.Key("rpc.system")
+	RPCServiceKey = attribute.Key("rpc.service")
+	RPCMethodKey  = attribute.Key("rpc.method")
+	TidKey        = attribute.Key("nakivo.tid")
+	BatchSizeKey  = attribute.Key("nakivo.batch.size")
+	ErrorCauseKey = attribute.Key("nakivo.error.cause")
+	ErrorKindKey  = attribute.Key("nakivo.error.kind")
+	HTTPStatusKey = attribute.Key("http.response.status_code")
+)
+
+const rpcSystemValue = "nakivo"
+
+type config struct {
+	tracerProvider trace.TracerProvider
+}
+
+// Option configures the tracing middleware.
+type Option func(*config)
+
+// WithTracerProvider sets the provider the tracer is taken from. Defaults to the global one.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// Middleware returns middleware creating a client span for every exchange with the director,
+// named after the action and method of the request, as child of the span in the context of the
+// caller. A batch gets a span of its own with a child span per request.
+func Middleware(opts ...Option) nakivo.Middleware {
+	cfg := config{tracerProvider: otel.GetTracerProvider()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	tracer := cfg.tracerProvider.Tracer(instrumentationName)
+
+	return func(next nakivo.Handler) nakivo.Handler {
+		return func(ctx context.Context, ex *nakivo.Exchange) error {
+			if !ex.Batch() {
+				request := ex.Requests[0]
+				ctx, span := tracer.Start(ctx, spanName(request),
+					trace.WithSpanKind(trace.SpanKindClient),
+					trace.WithAttributes(requestAttributes(request)...),
+				)
+				defer span.End()
+
+				err := next(ctx, ex)
+				end(span, ex, err)
+				return err
+			}
+
+			ctx, span := tracer.Start(ctx, "nakivo.batch",
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(RPCSystemKey.String(rpcSystemValue), BatchSizeKey.Int(len(ex.Requests))),
+			)
+			defer span.End()
+
+			spans := make([]trace.Span, len(ex.Requests))
+			for i, request := range ex.Requests {
+				_, spans[i] = tracer.Start(ctx, spanName(request),
+					trace.WithSpanKind(trace.SpanKindClient),
+					trace.WithAttributes(requestAttributes(request)...),
+				)
+			}
+
+			err := next(ctx, ex)
+			for i, s := range spans {
+				callErr := err
+				if callErr == nil {
+					callErr = ex.CallErr(i)
+				}
+				end(s, ex, callErr)
+				s.End()
+			}
+			end(span, ex, err)
+			return err
+		}
+	}
+}
+
+func spanName(request *nakivo.Request) string {
+	return request.Action + "/" + request.Method
+}
+
+func requestAttributes(request *nakivo.Request) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		RPCSystemKey.String(rpcSystemValue),
+		RPCServiceKey.String(request.Action),
+		RPCMethodKey.String(request.Method),
+		TidKey.Int(request.Tid),
+	}
+}
+
+// end records the outcome of the exchange on span.
+func end(span trace.Span, ex *nakivo.Exchange, err error) {
+	if ex.HTTPResponse != nil {
+		span.SetAttributes(HTTPStatusKey.Int(ex.HTTPResponse.StatusCode))
+	}
+	if err == nil {
+		return
+	}
+
+	var apiErr *nakivo.APIError
+	if errors.As(err, &apiErr) && apiErr.Cause != "" {
+		span.SetAttributes(ErrorCauseKey.String(apiErr.Cause))
+	}
+	var e *nakivo.Error
+	if errors.As(err, &e) && e.Kind != nil {
+		span.SetAttributes(ErrorKindKey.String(e.Kind.Error()))
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
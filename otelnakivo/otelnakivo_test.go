@@ -0,0 +1,129 @@
+package otelnakivo_test
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/peertechde/go-nakivo"
+	"github.com/peertechde/go-nakivo/nakivotest"
+	"github.com/peertechde/go-nakivo/otelnakivo"
+)
+
+// tracedClient returns a client of a fake director logged in as admin, recording its spans.
+func tracedClient(t *testing.T) (*nakivotest.Server, *nakivo.Client, *tracetest.SpanRecorder, *sdktrace.TracerProvider) {
+	t.Helper()
+	srv := nakivotest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddUser("admin", "secret")
+	srv.AddJob(nakivo.Job{Id: 10, Vid: "Job-10", Name: "Web servers", IsEnabled: true}, 0)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	client, err := srv.Client(nakivo.WithMiddleware(otelnakivo.Middleware(otelnakivo.WithTracerProvider(provider))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.Authentication.Login(context.Background(), "admin", "secret", false); err != nil {
+		t.Fatal(err)
+	}
+	return srv, client, recorder, provider
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestMiddleware(t *testing.T) {
+	_, client, recorder, provider := tracedClient(t)
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "report")
+	if _, _, err := client.Job.JobInfo(ctx, []int{10}, 0); err != nil {
+		t.Fatal(err)
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 3 || spans[0].Name() != "AuthenticationManagement/login" || spans[1].Name() != "JobSummaryManagement/getJobInfo" {
+		t.Fatalf("unexpected spans %v", spans)
+	}
+	span := spans[1]
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("expected the span to be a child of the span of the caller")
+	}
+	attrs := attributes(span)
+	if attrs[otelnakivo.RPCSystemKey].AsString() != "nakivo" || attrs[otelnakivo.RPCServiceKey].AsString() != nakivo.JobAction ||
+		attrs[otelnakivo.RPCMethodKey].AsString() != "getJobInfo" || attrs[otelnakivo.HTTPStatusKey].AsInt64() != 200 {
+		t.Errorf("unexpected attributes %v", span.Attributes())
+	}
+	if tid := attrs[otelnakivo.TidKey].AsInt64(); tid == 0 || tid == attributes(spans[0])[otelnakivo.TidKey].AsInt64() {
+		t.Errorf("expected the tid of the request, got %d", tid)
+	}
+	if span.Status().Code != codes.Unset {
+		t.Errorf("expected no error, got %v", span.Status())
+	}
+}
+
+func TestMiddlewareError(t *testing.T) {
+	srv, client, recorder, _ := tracedClient(t)
+
+	srv.InjectError(nakivo.JobAction, "getJobInfo", &nakivo.APIError{Message: "Permission denied", Cause: "AccessDeniedException"}, 1)
+	if _, _, err := client.Job.JobInfo(context.Background(), []int{10}, 0); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	spans := recorder.Ended()
+	span := spans[len(spans)-1]
+	attrs := attributes(span)
+	if span.Status().Code != codes.Error || attrs[otelnakivo.ErrorKindKey].AsString() != "permission denied" ||
+		attrs[otelnakivo.ErrorCauseKey].AsString() != "AccessDeniedException" {
+		t.Errorf("unexpected status %v and attributes %v", span.Status(), span.Attributes())
+	}
+	if len(span.Events()) != 1 || span.Events()[0].Name != "exception" {
+		t.Errorf("expected the error to be recorded, got %v", span.Events())
+	}
+}
+
+func TestMiddlewareBatch(t *testing.T) {
+	_, client, recorder, _ := tracedClient(t)
+
+	batch := client.NewBatch()
+	ok := batch.Add(nakivo.JobAction, "getJobInfo", []interface{}{[]int{10}, 0}, nil)
+	failed := batch.Add(nakivo.JobAction, "getNothing", nil, nil)
+	if _, err := batch.Do(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 4 {
+		t.Fatalf("expected the spans of the login, the batch and its 2 requests, got %v", spans)
+	}
+	calls, span := spans[1:3], spans[3]
+	if span.Name() != "nakivo.batch" || attributes(span)[otelnakivo.BatchSizeKey].AsInt64() != 2 || span.Status().Code != codes.Unset {
+		t.Errorf("unexpected span of the batch %s %v %v", span.Name(), span.Attributes(), span.Status())
+	}
+	for i, call := range []*nakivo.BatchCall{ok, failed} {
+		s := calls[i]
+		attrs := attributes(s)
+		if s.Name() != nakivo.JobAction+"/"+call.Request.Method || s.Parent().SpanID() != span.SpanContext().SpanID() {
+			t.Errorf("expected the span of %s as child of the batch, got %s", call.Request.Method, s.Name())
+		}
+		if attrs[otelnakivo.TidKey].AsInt64() != int64(call.Request.Tid) {
+			t.Errorf("expected tid %d, got %v", call.Request.Tid, attrs[otelnakivo.TidKey])
+		}
+	}
+	if calls[0].Status().Code != codes.Unset {
+		t.Errorf("expected no error for getJobInfo, got %v", calls[0].Status())
+	}
+	if calls[1].Status().Code != codes.Error || attributes(calls[1])[otelnakivo.ErrorCauseKey].AsString() == "" {
+		t.Errorf("expected the error of getNothing, got %v %v", calls[1].Status(), calls[1].Attributes())
+	}
+}
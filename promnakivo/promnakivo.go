@@ -0,0 +1,192 @@
+// Package promnakivo exports Prometheus metrics of the calls of a nakivo.Client.
+//
+//	collector := promnakivo.NewCollector()
+//	prometheus.MustRegister(collector)
+//
+//	policy := nakivo.DefaultRetryPolicy
+//	policy.OnAttempt = collector.OnAttempt
+//	client, err := nakivo.NewClient(nil, address, port,
+//		nakivo.WithMiddleware(collector.Middleware()),
+//		nakivo.WithRetryPolicy(policy),
+//	)
+package promnakivo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peertechde/go-nakivo"
+)
+
+const (
+	defaultNamespace = "nakivo"
+	subsystem        = "client"
+)
+
+// Collector records request counts, latencies, errors and retries of the calls of a client. It
+// implements prometheus.Collector.
+type Collector struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+	retries  *prometheus.CounterVec
+}
+
+type config struct {
+	namespace string
+	buckets   []float64
+	labels    prometheus.Labels
+}
+
+// Option configures a Collector.
+type Option func(*config)
+
+// WithNamespace sets the namespace of the metrics. Defaults to "nakivo".
+func WithNamespace(namespace string) Option {
+	return func(c *config) {
+		c.namespace = namespace
+	}
+}
+
+// WithBuckets sets the buckets of the latency histogram in seconds.
+func WithBuckets(buckets []float64) Option {
+	return func(c *config) {
+		c.buckets = buckets
+	}
+}
+
+// WithConstLabels adds constant labels to all metrics, e.g. the director a client talks to.
+func WithConstLabels(labels prometheus.Labels) Option {
+	return func(c *config) {
+		c.labels = labels
+	}
+}
+
+func NewCollector(opts ...Option) *Collector {
+	cfg := config{
+		namespace: defaultNamespace,
+		buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   cfg.namespace,
+			Subsystem:   subsystem,
+			Name:        "requests_total",
+			Help:        "Number of requests sent to the director, including logins and retries.",
+			ConstLabels: cfg.labels,
+		}, []string{"action", "method"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   cfg.namespace,
+			Subsystem:   subsystem,
+			Name:        "request_duration_seconds",
+			Help:        "Latency of requests sent to the director.",
+			Buckets:     cfg.buckets,
+			ConstLabels: cfg.labels,
+		}, []string{"action", "method"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   cfg.namespace,
+			Subsystem:   subsystem,
+			Name:        "errors_total",
+			Help:        "Number of failed requests by kind of error.",
+			ConstLabels: cfg.labels,
+		}, []string{"action", "method", "kind"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   cfg.namespace,
+			Subsystem:   subsystem,
+			Name:        "retries_total",
+			Help:        "Number of retried requests.",
+			ConstLabels: cfg.labels,
+		}, []string{"action", "method"}),
+	}
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.duration.Describe(ch)
+	c.errors.Describe(ch)
+	c.retries.Describe(ch)
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.duration.Collect(ch)
+	c.errors.Collect(ch)
+	c.retries.Collect(ch)
+}
+
+// Middleware returns middleware recording every exchange with the director. Requests of a
+// batch are recorded individually with the latency of the batch.
+func (c *Collector) Middleware() nakivo.Middleware {
+	return func(next nakivo.Handler) nakivo.Handler {
+		return func(ctx context.Context, ex *nakivo.Exchange) error {
+			start := time.Now()
+			err := next(ctx, ex)
+			elapsed := time.Since(start).Seconds()
+
+			for i, request := range ex.Requests {
+				c.requests.WithLabelValues(request.Action, request.Method).Inc()
+				c.duration.WithLabelValues(request.Action, request.Method).Observe(elapsed)
+
+				callErr := err
+				if callErr == nil {
+					callErr = ex.CallErr(i)
+				}
+				if callErr != nil {
+					c.errors.WithLabelValues(request.Action, request.Method, ErrorKind(callErr)).Inc()
+				}
+			}
+			return err
+		}
+	}
+}
+
+// OnAttempt counts retries. Use it as nakivo.RetryPolicy.OnAttempt.
+func (c *Collector) OnAttempt(attempt nakivo.Attempt) {
+	if attempt.Number < 2 {
+		return
+	}
+	for _, request := range attempt.Requests {
+		c.retries.WithLabelValues(request.Action, request.Method).Inc()
+	}
+}
+
+// errorKinds maps the kinds of errors to their label values.
+var errorKinds = []struct {
+	kind  error
+	label string
+}{
+	{nakivo.ErrAuthenticationRequired, "authentication_required"},
+	{nakivo.ErrPermissionDenied, "permission_denied"},
+	{nakivo.ErrNotFound, "not_found"},
+	{nakivo.ErrLicenseViolation, "license_violation"},
+	{nakivo.ErrLockout, "lockout"},
+	{nakivo.ErrServerError, "server_error"},
+	{nakivo.ErrMalformedResponse, "malformed_response"},
+	{context.Canceled, "canceled"},
+	{context.DeadlineExceeded, "deadline_exceeded"},
+}
+
+// ErrorKind returns the label value of the kind of err.
+func ErrorKind(err error) string {
+	for _, k := range errorKinds {
+		if errors.Is(err, k.kind) {
+			return k.label
+		}
+	}
+	var apiErr *nakivo.APIError
+	if errors.As(err, &apiErr) {
+		return "api"
+	}
+	var e *nakivo.Error
+	if errors.As(err, &e) && e.StatusCode == 0 {
+		return "transport"
+	}
+	return "unknown"
+}
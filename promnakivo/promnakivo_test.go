@@ -0,0 +1,118 @@
+package promnakivo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/peertechde/go-nakivo"
+	"github.com/peertechde/go-nakivo/nakivotest"
+	"github.com/peertechde/go-nakivo/promnakivo"
+)
+
+// measuredClient returns a client of a fake director logged in as admin, recording its metrics
+// into collector.
+func measuredClient(t *testing.T, collector *promnakivo.Collector) (*nakivotest.Server, *nakivo.Client) {
+	t.Helper()
+	srv := nakivotest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddUser("admin", "secret")
+	srv.AddJob(nakivo.Job{Id: 10, Vid: "Job-10", Name: "Web servers", IsEnabled: true}, 0)
+
+	client, err := srv.Client(
+		nakivo.WithMiddleware(collector.Middleware()),
+		nakivo.WithRetryPolicy(nakivo.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, OnAttempt: collector.OnAttempt}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.Authentication.Login(context.Background(), "admin", "secret", false); err != nil {
+		t.Fatal(err)
+	}
+	return srv, client
+}
+
+// counter returns the exposition of a counter of the client subsystem.
+func counter(name, help string, samples ...string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# HELP nakivo_client_%s %s\n# TYPE nakivo_client_%s counter\n", name, help, name)
+	for _, sample := range samples {
+		fmt.Fprintf(&b, "nakivo_client_%s%s\n", name, sample)
+	}
+	return b.String()
+}
+
+func TestRetries(t *testing.T) {
+	collector := promnakivo.NewCollector()
+	srv, client := measuredClient(t, collector)
+
+	srv.InjectHTTPError(nakivo.JobAction, "getJobInfo", http.StatusServiceUnavailable, 2)
+	if _, _, err := client.Job.JobInfo(context.Background(), []int{10}, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	want := counter("requests_total", "Number of requests sent to the director, including logins and retries.",
+		`{action="AuthenticationManagement",method="login"} 1`,
+		`{action="JobSummaryManagement",method="getJobInfo"} 3`,
+	) + counter("errors_total", "Number of failed requests by kind of error.",
+		`{action="JobSummaryManagement",kind="server_error",method="getJobInfo"} 2`,
+	) + counter("retries_total", "Number of retried requests.",
+		`{action="JobSummaryManagement",method="getJobInfo"} 2`,
+	)
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(want),
+		"nakivo_client_requests_total", "nakivo_client_errors_total", "nakivo_client_retries_total"); err != nil {
+		t.Error(err)
+	}
+	if n := testutil.CollectAndCount(collector, "nakivo_client_request_duration_seconds"); n != 2 {
+		t.Errorf("expected the latencies of 2 methods, got %d", n)
+	}
+}
+
+func TestBatchErrors(t *testing.T) {
+	collector := promnakivo.NewCollector()
+	srv, client := measuredClient(t, collector)
+
+	srv.InjectError(nakivo.JobAction, "getGroupInfo", &nakivo.APIError{Message: "Permission denied", Cause: "AccessDeniedException"}, 1)
+	batch := client.NewBatch()
+	batch.Add(nakivo.JobAction, "getJobInfo", []interface{}{[]int{10}, 0}, nil)
+	batch.Add(nakivo.JobAction, "getGroupInfo", []interface{}{[]interface{}{nil}, 0, false}, nil)
+	batch.Add(nakivo.AuthenticationAction, "login", []interface{}{"admin", "wrong", false}, nil)
+	if _, err := batch.Do(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := counter("errors_total", "Number of failed requests by kind of error.",
+		`{action="AuthenticationManagement",kind="authentication_required",method="login"} 1`,
+		`{action="JobSummaryManagement",kind="permission_denied",method="getGroupInfo"} 1`,
+	)
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(want), "nakivo_client_errors_total"); err != nil {
+		t.Error(err)
+	}
+	if got := testutil.CollectAndCount(collector, "nakivo_client_retries_total"); got != 0 {
+		t.Errorf("expected no retries, got %d", got)
+	}
+}
+
+func TestErrorKind(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{err: &nakivo.Error{Kind: nakivo.ErrLockout}, want: "lockout"},
+		{err: &nakivo.Error{Err: &nakivo.APIError{Message: "Something went wrong", Cause: "RuntimeException"}}, want: "api"},
+		{err: &nakivo.Error{Err: errors.New("connection refused")}, want: "transport"},
+		{err: fmt.Errorf("wait failed (%w)", context.DeadlineExceeded), want: "deadline_exceeded"},
+		{err: errors.New("boom"), want: "unknown"},
+	}
+	for _, tt := range tests {
+		if got := promnakivo.ErrorKind(tt.err); got != tt.want {
+			t.Errorf("ErrorKind(%v) = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package nakivo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSnippetLength is the maximum number of bytes of a body included in a ResponseBodyError.
+const maxSnippetLength = 256
+
+var errEmptyBody = errors.New("empty response body")
+
+// ResponseBodyError is returned if the body of a response is empty, is not JSON (e.g. an HTML
+// login page or an error page of a proxy) or can't be decoded.
+type ResponseBodyError struct {
+	// Content type of the response
+	ContentType string
+
+	// Beginning of the body, truncated to a few hundred bytes
+	Snippet string
+
+	// Err describes why the body was rejected.
+	Err error
+}
+
+func (err *ResponseBodyError) Error() string {
+	if err.Snippet == "" {
+		return err.Err.Error()
+	}
+	return fmt.Sprintf("%s: %q", err.Err, err.Snippet)
+}
+
+func (err *ResponseBodyError) Unwrap() error {
+	return err.Err
+}
+
+// decodeBody decodes the JSON body of a response into v.
+func decodeBody(contentType string, body []byte, v interface{}) error {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return &ResponseBodyError{ContentType: contentType, Err: errEmptyBody}
+	}
+	if !isJSON(contentType, trimmed) {
+		return &ResponseBodyError{
+			ContentType: contentType,
+			Snippet:     snippet(trimmed),
+			Err:         fmt.Errorf("unexpected content type '%s'", contentType),
+		}
+	}
+	if err := json.Unmarshal(trimmed, v); err != nil {
+		return &ResponseBodyError{
+			ContentType: contentType,
+			Snippet:     snippet(trimmed),
+			Err:         fmt.Errorf("decode response failed (%w)", err),
+		}
+	}
+	return nil
+}
+
+// isJSON reports whether a body is JSON. The router is lenient with the content type it sends,
+// so a body that looks like JSON is accepted unless the content type states otherwise.
+func isJSON(contentType string, body []byte) bool {
+	if contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")) {
+			return true
+		}
+		if err == nil && (mediaType == "text/html" || mediaType == "application/xml" || mediaType == "text/xml") {
+			return false
+		}
+	}
+	return body[0] == '{' || body[0] == '['
+}
+
+// snippet returns the beginning of body with whitespace collapsed.
+func snippet(body []byte) string {
+	truncated := len(body) > maxSnippetLength
+	if truncated {
+		body = body[:maxSnippetLength]
+		// don't cut a multi-byte character in half
+		for len(body) > 0 && !utf8.Valid(body) {
+			body = body[:len(body)-1]
+		}
+	}
+	s := strings.Join(strings.Fields(string(body)), " ")
+	if truncated {
+		s += "..."
+	}
+	return s
+}
@@ -0,0 +1,63 @@
+package nakivo_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/peertechde/go-nakivo"
+)
+
+func TestNonJSONBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		kind        error
+		snippet     string
+	}{
+		{name: "login page", status: http.StatusOK, contentType: "text/html", body: "<html><body>Login</body></html>", kind: nakivo.ErrMalformedResponse, snippet: "<html><body>Login</body></html>"},
+		{name: "proxy error", status: http.StatusBadGateway, contentType: "text/html", body: "<html>Bad Gateway</html>", kind: nakivo.ErrServerError, snippet: "<html>Bad Gateway</html>"},
+		{name: "empty", status: http.StatusOK, contentType: "application/json", kind: nakivo.ErrMalformedResponse},
+		{name: "truncated", status: http.StatusOK, contentType: "application/json", body: `{"action":"JobSummaryManagement","data":{`, kind: nakivo.ErrMalformedResponse},
+		{name: "long", status: http.StatusOK, contentType: "text/plain", body: strings.Repeat("x", 4096), kind: nakivo.ErrMalformedResponse, snippet: strings.Repeat("x", 256) + "..."},
+		{name: "long multi-byte", status: http.StatusOK, contentType: "text/plain", body: "x" + strings.Repeat("ä", 200), kind: nakivo.ErrMalformedResponse, snippet: "x" + strings.Repeat("ä", 127) + "..."},
+		{name: "at the limit", status: http.StatusOK, contentType: "text/plain", body: strings.Repeat("x", 256), kind: nakivo.ErrMalformedResponse, snippet: strings.Repeat("x", 256)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			_, _, err := client.Job.JobInfo(context.Background(), []int{10}, 0)
+			if !errors.Is(err, tt.kind) {
+				t.Fatalf("expected %v, got %v", tt.kind, err)
+			}
+			var bodyErr *nakivo.ResponseBodyError
+			if !errors.As(err, &bodyErr) {
+				t.Fatalf("expected *ResponseBodyError, got %v", err)
+			}
+			if bodyErr.ContentType != tt.contentType {
+				t.Errorf("expected content type %s, got %s", tt.contentType, bodyErr.ContentType)
+			}
+			if tt.snippet != "" && bodyErr.Snippet != tt.snippet {
+				t.Errorf("expected snippet %q, got %q", tt.snippet, bodyErr.Snippet)
+			}
+			if len(bodyErr.Snippet) > 512 {
+				t.Errorf("expected a truncated snippet, got %d bytes", len(bodyErr.Snippet))
+			}
+		})
+	}
+}
+
+func TestEmptyBodyWithoutResult(t *testing.T) {
+	client := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	if _, _, err := client.Call(context.Background(), "JobManagement", "runJob", nil, nil); err != nil {
+		t.Fatalf("expected an empty body to be accepted without result, got %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package nakivo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RetryPolicy describes how requests failing with transient errors, i.e. transport errors and
+// 5xx responses, are retried.
+type RetryPolicy struct {
+	// Maximum number of attempts including the first one. Values below 2 disable retries.
+	MaxAttempts int
+
+	// Backoff before the first retry
+	InitialBackoff time.Duration
+
+	// Upper bound of the backoff between two attempts. Zero means no bound.
+	MaxBackoff time.Duration
+
+	// Factor the backoff grows by after every attempt. Values below 1 keep the backoff constant.
+	Multiplier float64
+
+	// Fraction (0 to 1) of the backoff which is randomized to spread retries of concurrent
+	// clients
+	Jitter float64
+
+	// Retryable reports whether a method of an action may be retried. Only methods without side
+	// effects should be retried. If nil, IsIdempotent is used.
+	Retryable func(action, method string) bool
+
+	// OnAttempt, if set, is called after every attempt
+	OnAttempt func(Attempt)
+}
+
+// DefaultRetryPolicy retries idempotent requests up to three times with exponential backoff.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    4,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+// Attempt describes a single attempt to send one or more requests.
+type Attempt struct {
+	// Requests sent with the attempt. Holds more than one request for batches.
+	Requests []*Request
+
+	// Number of the attempt, starting at 1
+	Number int
+
+	// Response of the attempt. nil on transport errors.
+	Response *http.Response
+
+	// Error of the attempt, nil on success
+	Err error
+
+	// Backoff before the next attempt. Zero if the request is not retried anymore.
+	Backoff time.Duration
+}
+
+// idempotentMethods are the methods which only read state from the director, keyed by action.
+var idempotentMethods = map[string]map[string]bool{
+	AuthenticationAction: {
+		"isLogged": true,
+	},
+	JobAction: {
+		"getGroupInfo": true,
+		"getJobInfo":   true,
+	},
+}
+
+// IsIdempotent reports whether method of action only reads state from the director and can
+// therefore safely be retried.
+func IsIdempotent(action, method string) bool {
+	return idempotentMethods[action][method]
+}
+
+// retry calls send until it succeeds, fails with a permanent error or the policy gives up. The
+// requests are only retried if all of them are retryable.
+func (c *Client) retry(ctx context.Context, requests []*Request, send func() (*http.Response, error)) (*http.Response, error) {
+	policy := c.retryPolicy
+	retryable := policy.MaxAttempts > 1
+	for _, request := range requests {
+		if !retryable {
+			break
+		}
+		retryable = policy.retryable(request.Action, request.Method)
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := send()
+
+		var backoff time.Duration
+		giveUp := err == nil || !retryable || attempt >= policy.MaxAttempts || !isTransient(ctx, resp, err)
+		if !giveUp {
+			backoff = policy.backoff(attempt)
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < backoff {
+				giveUp, backoff = true, 0
+			}
+		}
+		if policy.OnAttempt != nil {
+			policy.OnAttempt(Attempt{Requests: requests, Number: attempt, Response: resp, Err: err, Backoff: backoff})
+		}
+		if giveUp {
+			return resp, err
+		}
+
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return resp, err
+		case <-t.C:
+		}
+	}
+}
+
+func (p *RetryPolicy) retryable(action, method string) bool {
+	if p.Retryable != nil {
+		return p.Retryable(action, method)
+	}
+	return IsIdempotent(action, method)
+}
+
+// backoff returns the time to wait after the given attempt.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+	if jitter := math.Min(math.Max(p.Jitter, 0), 1); jitter > 0 {
+		backoff += backoff * jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(backoff)
+}
+
+// isTransient reports whether a failed attempt may succeed if repeated: transport errors and
+// server errors are, cancellation of the context is not.
+func isTransient(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if resp == nil {
+		var urlErr *url.Error
+		return errors.As(err, &urlErr)
+	}
+	return resp.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,85 @@
+package nakivo_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/peertechde/go-nakivo"
+)
+
+// failing returns a handler answering the first n requests with status and the following ones
+// with handler. hits counts all requests.
+func failing(n int, status int, hits *int32, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if int(atomic.AddInt32(hits, 1)) <= n {
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func answerTrue(request *nakivo.Request) *nakivo.Response {
+	return &nakivo.Response{Data: true}
+}
+
+func TestRetryServerError(t *testing.T) {
+	var hits int32
+	var attempts []nakivo.Attempt
+	client := testClient(t, failing(2, http.StatusServiceUnavailable, &hits, router(answerTrue)), nakivo.WithRetryPolicy(nakivo.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		OnAttempt:      func(a nakivo.Attempt) { attempts = append(attempts, a) },
+	}))
+
+	var loggedIn bool
+	if _, _, err := client.Call(context.Background(), nakivo.AuthenticationAction, "isLogged", nil, &loggedIn); err != nil {
+		t.Fatal(err)
+	}
+	if !loggedIn || hits != 3 {
+		t.Errorf("expected a result after 3 requests, got %t after %d", loggedIn, hits)
+	}
+	if len(attempts) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(attempts))
+	}
+	for i, a := range attempts[:2] {
+		if !errors.Is(a.Err, nakivo.ErrServerError) || a.Number != i+1 || a.Backoff <= 0 {
+			t.Errorf("expected attempt %d to fail with a server error and back off, got %v after %s", i+1, a.Err, a.Backoff)
+		}
+	}
+	if attempts[2].Err != nil || attempts[2].Backoff != 0 {
+		t.Errorf("expected the last attempt to succeed, got %v", attempts[2].Err)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	var hits int32
+	client := testClient(t, failing(5, http.StatusBadGateway, &hits, router(answerTrue)),
+		nakivo.WithRetryPolicy(nakivo.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}))
+
+	_, _, err := client.Call(context.Background(), nakivo.AuthenticationAction, "isLogged", nil, nil)
+	if !errors.Is(err, nakivo.ErrServerError) {
+		t.Fatalf("expected ErrServerError, got %v", err)
+	}
+	if hits != 2 {
+		t.Errorf("expected 2 attempts, got %d", hits)
+	}
+}
+
+func TestRetrySkipsNonIdempotent(t *testing.T) {
+	var hits int32
+	client := testClient(t, failing(1, http.StatusInternalServerError, &hits, router(answerTrue)),
+		nakivo.WithRetryPolicy(nakivo.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+
+	_, _, err := client.Call(context.Background(), "JobManagement", "runJob", []interface{}{map[string]interface{}{"jobId": []int{10}}}, nil)
+	if !errors.Is(err, nakivo.ErrServerError) {
+		t.Fatalf("expected ErrServerError, got %v", err)
+	}
+	if hits != 1 {
+		t.Errorf("expected a single attempt, got %d", hits)
+	}
+}
@@ -0,0 +1,387 @@
+package nakivo
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// scheduleHorizon bounds the number of days searched for the next run of a schedule, so rules
+// which never match, like the 31st of February, terminate.
+const scheduleHorizon = 8 * 366
+
+// PlannedRun is a run of a job computed from its schedules.
+type PlannedRun struct {
+	// Time the run starts at
+	Time time.Time
+
+	// Job which runs
+	Job *Job
+
+	// Schedule the run is planned by
+	Schedule *Schedule
+
+	// Run of the job triggering this one, nil unless the schedule is a TRIGGER schedule
+	Trigger *PlannedRun
+}
+
+// Planner computes the runs of jobs from their schedules, without asking the director. Runs of
+// TRIGGER schedules are estimated from the planned runs of the triggering job and its average
+// duration.
+type Planner struct {
+	// jobs by vid, and in the order handed to NewPlanner
+	jobs  map[string]*Job
+	order []*Job
+}
+
+// NewPlanner returns a planner for jobs. Jobs triggering one of the jobs must be part of jobs
+// to plan the runs they trigger, runs triggered by unknown jobs are not planned.
+func NewPlanner(jobs []Job) *Planner {
+	p := &Planner{jobs: make(map[string]*Job, len(jobs))}
+	for i := range jobs {
+		p.jobs[jobs[i].Vid] = &jobs[i]
+		p.order = append(p.order, &jobs[i])
+	}
+	return p
+}
+
+// NextRuns returns the next n runs of job after from, across all enabled schedules of the job.
+// If several schedules start the job at the same time, the run is planned by the schedule with
+// the highest priority, i.e. the lowest position.
+func (p *Planner) NextRuns(job *Job, from time.Time, n int) ([]PlannedRun, error) {
+	return p.nextRuns(job, from, time.Time{}, n, map[string]bool{})
+}
+
+// NextRuns returns the next n runs of the job after from. Runs of TRIGGER schedules are not
+// planned, use a Planner to plan them.
+func (job *Job) NextRuns(from time.Time, n int) ([]PlannedRun, error) {
+	return (&Planner{}).NextRuns(job, from, n)
+}
+
+// nextRuns returns the next n runs of job after from and before to. A zero to doesn't bound
+// the runs.
+func (p *Planner) nextRuns(job *Job, from, to time.Time, n int, visiting map[string]bool) ([]PlannedRun, error) {
+	if n <= 0 || !job.IsEnabled {
+		return nil, nil
+	}
+	if visiting[job.Vid] {
+		return nil, fmt.Errorf("job %s triggers itself", job.Name)
+	}
+	visiting[job.Vid] = true
+	defer delete(visiting, job.Vid)
+
+	var runs []PlannedRun
+	for i := range job.Schedules {
+		schedule := &job.Schedules[i]
+		if !schedule.Enabled {
+			continue
+		}
+		var (
+			scheduled []PlannedRun
+			err       error
+		)
+		if schedule.Type == ScheduleTypeTrigger {
+			scheduled, err = p.triggeredRuns(job, schedule, from, to, n, visiting)
+		} else {
+			scheduled, err = scheduledRuns(job, schedule, from, to, n)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("schedule %d of job %s (%w)", schedule.Position, job.Name, err)
+		}
+		runs = append(runs, scheduled...)
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool {
+		if !runs[i].Time.Equal(runs[j].Time) {
+			return runs[i].Time.Before(runs[j].Time)
+		}
+		return runs[i].Schedule.Position < runs[j].Schedule.Position
+	})
+	planned := make([]PlannedRun, 0, len(runs))
+	for _, run := range runs {
+		if len(planned) > 0 && planned[len(planned)-1].Time.Equal(run.Time) {
+			continue
+		}
+		planned = append(planned, run)
+		if len(planned) == n {
+			break
+		}
+	}
+	return planned, nil
+}
+
+// scheduledRuns returns the next n runs of a time based schedule after from and before to.
+func scheduledRuns(job *Job, schedule *Schedule, from, to time.Time, n int) ([]PlannedRun, error) {
+	times, err := schedule.runs(from, to, n)
+	if err != nil {
+		return nil, err
+	}
+	runs := make([]PlannedRun, len(times))
+	for i, t := range times {
+		runs[i] = PlannedRun{Time: t, Job: job, Schedule: schedule}
+	}
+	return runs, nil
+}
+
+// triggeredRuns estimates the next n runs of a TRIGGER schedule after from and before to: each
+// planned run of the triggering job triggers a run once it finished after its average
+// duration, plus the delay of the schedule.
+func (p *Planner) triggeredRuns(job *Job, schedule *Schedule, from, to time.Time, n int, visiting map[string]bool) ([]PlannedRun, error) {
+	trigger, ok := p.jobs[schedule.TriggerItem]
+	if !ok {
+		return nil, nil
+	}
+	delay, err := schedule.triggerDelay()
+	if err != nil {
+		return nil, err
+	}
+	offset := trigger.AverageDuration() + delay
+
+	if !to.IsZero() {
+		to = to.Add(-offset)
+	}
+	triggers, err := p.nextRuns(trigger, from.Add(-offset), to, n, visiting)
+	if err != nil {
+		return nil, err
+	}
+	var runs []PlannedRun
+	for i := range triggers {
+		start := triggers[i].Time.Add(offset)
+		if !start.After(from) {
+			continue
+		}
+		runs = append(runs, PlannedRun{Time: start, Job: job, Schedule: schedule, Trigger: &triggers[i]})
+	}
+	return runs, nil
+}
+
+// Next returns the first run of the schedule after from, ok is false if the schedule never
+// runs again. TRIGGER schedules don't run on their own and are reported as not running.
+func (schedule *Schedule) Next(from time.Time) (next time.Time, ok bool, err error) {
+	runs, err := schedule.runs(from, time.Time{}, 1)
+	if err != nil || len(runs) == 0 {
+		return time.Time{}, false, err
+	}
+	return runs[0], true, nil
+}
+
+// runs returns the next n runs of the schedule after from and before to, generated day by day.
+// A zero to doesn't bound the runs. The search ends once the schedule didn't run for
+// scheduleHorizon days.
+func (schedule *Schedule) runs(from, to time.Time, n int) ([]time.Time, error) {
+	switch schedule.Type {
+	case ScheduleTypeNone, ScheduleTypeTrigger:
+		return nil, nil
+	case ScheduleTypeDaily, ScheduleTypePeriodically, ScheduleTypeMonthlyYearly:
+	default:
+		return nil, fmt.Errorf("unsupported schedule type %s", schedule.Type)
+	}
+
+	loc := schedule.Location()
+	start, ok, err := schedule.StartClock()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("no start time")
+	}
+	effective, err := schedule.EffectiveFrom()
+	if err != nil {
+		return nil, err
+	}
+	if from.Before(effective) {
+		// runs start at the effective date at the earliest
+		from = effective.Add(-time.Nanosecond)
+	}
+	var every, window time.Duration
+	if schedule.Type == ScheduleTypePeriodically {
+		if every, err = schedule.period(); err != nil {
+			return nil, err
+		}
+		if window, _, err = schedule.window(start); err != nil {
+			return nil, err
+		}
+	}
+
+	// start the day before, as periodic runs of that day may last beyond midnight
+	from = from.In(loc)
+	day := time.Date(from.Year(), from.Month(), from.Day()-1, 0, 0, 0, 0, loc)
+	var runs []time.Time
+	for idle := 0; idle < scheduleHorizon && len(runs) < n; idle++ {
+		if !to.IsZero() && !day.Before(to) {
+			break
+		}
+		for _, t := range schedule.runsOn(day, start, every, window, effective) {
+			// runs of a day may reach into the runs of the next one
+			if !t.After(from) || (!to.IsZero() && !t.Before(to)) {
+				continue
+			}
+			runs = append(runs, t)
+			from, idle = t, 0
+			if len(runs) == n {
+				break
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return runs, nil
+}
+
+// runsOn returns the runs of the schedule starting on day, in chronological order. Periodic
+// runs start every period within the window after the start time.
+func (schedule *Schedule) runsOn(day time.Time, start, every, window time.Duration, effective time.Time) []time.Time {
+	// the start time is a wall clock time, which stays the same across daylight saving changes
+	at := time.Date(day.Year(), day.Month(), day.Day(), int(start/time.Hour), int(start%time.Hour/time.Minute),
+		int(start%time.Minute/time.Second), 0, day.Location())
+
+	switch schedule.Type {
+	case ScheduleTypeDaily:
+		if schedule.runsOnWeekday(day.Weekday()) {
+			return []time.Time{at}
+		}
+	case ScheduleTypePeriodically:
+		if schedule.EveryType == EveryTypeDay {
+			// runs count from the effective date, or else from the next run planned by the director
+			anchor := effective
+			if anchor.IsZero() {
+				anchor, _ = schedule.NextRunAt()
+			}
+			if anchor.IsZero() {
+				anchor = day
+			}
+			anchor = anchor.In(day.Location())
+			anchorDay := time.Date(anchor.Year(), anchor.Month(), anchor.Day(), 0, 0, 0, 0, day.Location())
+			days := int(day.Sub(anchorDay).Round(24*time.Hour) / (24 * time.Hour))
+			if days%schedule.Every == 0 && (days >= 0 || effective.IsZero()) {
+				return []time.Time{at}
+			}
+			return nil
+		}
+		if !schedule.runsOnWeekday(day.Weekday()) {
+			return nil
+		}
+		end := at.Add(window)
+		var runs []time.Time
+		for t := at; !t.After(end); t = t.Add(every) {
+			runs = append(runs, t)
+		}
+		return runs
+	case ScheduleTypeMonthlyYearly:
+		if schedule.Month != 0 && time.Month(schedule.Month) != day.Month() {
+			return nil
+		}
+		if schedule.runsOnDayOfMonth(day) {
+			return []time.Time{at}
+		}
+	}
+	return nil
+}
+
+// runsOnWeekday reports whether the bit of day is set in the On bit mask. The lowest bit is
+// Monday.
+func (schedule *Schedule) runsOnWeekday(day time.Weekday) bool {
+	return Days(schedule.On).Has(day)
+}
+
+// runsOnDayOfMonth reports whether a MONTHLY_YEARLY schedule runs on day. DayOfMonth beyond the
+// end of a month selects the last day of the month. DayOfWeek is 1 for Monday to 7 for Sunday.
+func (schedule *Schedule) runsOnDayOfMonth(day time.Time) bool {
+	last := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+	if schedule.MonthlyEveryType == MonthlyEveryTypeDay {
+		dayOfMonth := schedule.DayOfMonth
+		if dayOfMonth > last {
+			dayOfMonth = last
+		}
+		return day.Day() == dayOfMonth
+	}
+
+	if (int(day.Weekday())+6)%7+1 != schedule.DayOfWeek {
+		return false
+	}
+	switch schedule.MonthlyEveryType {
+	case MonthlyEveryTypeFirst:
+		return day.Day() <= 7
+	case MonthlyEveryTypeSecond:
+		return day.Day() > 7 && day.Day() <= 14
+	case MonthlyEveryTypeThird:
+		return day.Day() > 14 && day.Day() <= 21
+	case MonthlyEveryTypeFourth:
+		return day.Day() > 21 && day.Day() <= 28
+	case MonthlyEveryTypeLast:
+		return day.Day() > last-7
+	}
+	return false
+}
+
+// period returns the time between the runs of a PERIODICALLY schedule.
+func (schedule *Schedule) period() (time.Duration, error) {
+	if schedule.Every <= 0 {
+		return 0, fmt.Errorf("invalid period %d", schedule.Every)
+	}
+	if schedule.EveryType == EveryTypeDay {
+		return time.Duration(schedule.Every) * 24 * time.Hour, nil
+	}
+	unit, err := schedule.everyUnit()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(schedule.Every) * unit, nil
+}
+
+// window returns the time after the start time in which a PERIODICALLY schedule starts runs.
+// Without end time, runs continue until the start time of the next day. An end time at or
+// before the start time ends the runs on the next day, reported by wraps.
+func (schedule *Schedule) window(start time.Duration) (window time.Duration, wraps bool, err error) {
+	clock, ok, err := schedule.EndClock()
+	if err != nil {
+		return 0, false, err
+	}
+	if !ok {
+		return 24*time.Hour - time.Nanosecond, false, nil
+	}
+	if clock <= start {
+		return clock - start + 24*time.Hour, true, nil
+	}
+	return clock - start, false, nil
+}
+
+// triggerDelay returns the delay between the end of the triggering job and the start of the
+// job triggered by a TRIGGER schedule.
+func (schedule *Schedule) triggerDelay() (time.Duration, error) {
+	if schedule.TriggerRunType != TriggerRunTypeDelayed {
+		return 0, nil
+	}
+	unit, err := schedule.everyUnit()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(schedule.Every) * unit, nil
+}
+
+func (schedule *Schedule) everyUnit() (time.Duration, error) {
+	switch schedule.EveryType {
+	case EveryTypeSecond:
+		return time.Second, nil
+	case EveryTypeMinute:
+		return time.Minute, nil
+	case EveryTypeHour:
+		return time.Hour, nil
+	case EveryTypeDay:
+		return 24 * time.Hour, nil
+	}
+	return 0, fmt.Errorf("unsupported unit %s", schedule.EveryType)
+}
+
+// Location returns the time zone of the schedule. Timezone names unknown to the time zone
+// database fall back to a fixed zone with TimezoneOffsetMs.
+func (schedule *Schedule) Location() *time.Location {
+	if schedule.Timezone != "" {
+		if loc, err := time.LoadLocation(schedule.Timezone); err == nil {
+			return loc
+		}
+	}
+	if schedule.TimezoneOffsetMs != 0 {
+		return time.FixedZone(schedule.Timezone, schedule.TimezoneOffsetMs/1000)
+	}
+	return time.UTC
+}
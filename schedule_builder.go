@@ -0,0 +1,293 @@
+package nakivo
+
+import (
+	"fmt"
+	"time"
+)
+
+// Days is the bit mask of weekdays a schedule runs on, see Schedule.On.
+type Days int
+
+const (
+	Monday Days = 1 << iota
+	Tuesday
+	Wednesday
+	Thursday
+	Friday
+	Saturday
+	Sunday
+
+	// Weekdays are Monday to Friday
+	Weekdays = Monday | Tuesday | Wednesday | Thursday | Friday
+
+	// Weekend are Saturday and Sunday
+	Weekend = Saturday | Sunday
+
+	// AllDays are all days of the week
+	AllDays = Weekdays | Weekend
+)
+
+// DaysOf returns the bit mask of days.
+func DaysOf(days ...time.Weekday) Days {
+	var mask Days
+	for _, day := range days {
+		mask |= 1 << ((int(day) + 6) % 7)
+	}
+	return mask
+}
+
+// Has reports whether day is set in the mask.
+func (d Days) Has(day time.Weekday) bool {
+	return d&DaysOf(day) != 0
+}
+
+// ScheduleBuilder builds Schedule values, e.g.
+//
+//	NewSchedule().Daily().At(22, 0).On(Weekdays).Build()
+//	NewSchedule().Every(4, EveryTypeHour).Between(6, 0, 22, 0).Build()
+//	NewSchedule().Monthly(MonthlyEveryTypeLast, time.Friday).At(1, 0).Build()
+//
+// The first error is reported by Build.
+type ScheduleBuilder struct {
+	schedule Schedule
+	loc      *time.Location
+	err      error
+}
+
+// NewSchedule returns a builder for an enabled schedule running on all days at midnight in
+// UTC, until a rule is selected with Daily, Every, Monthly, MonthlyOnDay or TriggeredBy.
+func NewSchedule() *ScheduleBuilder {
+	return &ScheduleBuilder{schedule: Schedule{
+		Enabled:   true,
+		Type:      ScheduleTypeNone,
+		StartTime: formatClock(0),
+		Timezone:  "UTC",
+		On:        int(AllDays),
+	}}
+}
+
+// Daily runs the job once a day, at the start time on the days selected by On.
+func (b *ScheduleBuilder) Daily() *ScheduleBuilder {
+	b.schedule.Type = ScheduleTypeDaily
+	return b
+}
+
+// Every runs the job periodically every n units, between the times set by Between on the days
+// selected by On. With EveryTypeDay, the job runs every n days at the start time.
+func (b *ScheduleBuilder) Every(n int, unit EveryType) *ScheduleBuilder {
+	if n <= 0 {
+		return b.fail(fmt.Errorf("invalid period %d", n))
+	}
+	if !unit.Valid() {
+		return b.fail(fmt.Errorf("unsupported unit %s", unit))
+	}
+	b.schedule.Type = ScheduleTypePeriodically
+	b.schedule.Every = n
+	b.schedule.EveryType = unit
+	return b
+}
+
+// Monthly runs the job once a month on the first, second, third, fourth or last weekday of the
+// month, at the start time.
+func (b *ScheduleBuilder) Monthly(which MonthlyEveryType, weekday time.Weekday) *ScheduleBuilder {
+	if which == MonthlyEveryTypeDay || !which.Valid() {
+		return b.fail(fmt.Errorf("unsupported monthly type %s, use MonthlyOnDay to select a day", which))
+	}
+	b.schedule.Type = ScheduleTypeMonthlyYearly
+	b.schedule.MonthlyEveryType = which
+	b.schedule.DayOfWeek = (int(weekday)+6)%7 + 1
+	return b
+}
+
+// MonthlyOnDay runs the job once a month on day, at the start time. In months with fewer days,
+// the job runs on the last day of the month.
+func (b *ScheduleBuilder) MonthlyOnDay(day int) *ScheduleBuilder {
+	if day < 1 || day > 31 {
+		return b.fail(fmt.Errorf("invalid day of month %d", day))
+	}
+	b.schedule.Type = ScheduleTypeMonthlyYearly
+	b.schedule.MonthlyEveryType = MonthlyEveryTypeDay
+	b.schedule.DayOfMonth = day
+	return b
+}
+
+// InMonth restricts a monthly schedule to month, making it a yearly schedule.
+func (b *ScheduleBuilder) InMonth(month time.Month) *ScheduleBuilder {
+	if month < time.January || month > time.December {
+		return b.fail(fmt.Errorf("invalid month %d", month))
+	}
+	b.schedule.Month = int(month)
+	return b
+}
+
+// TriggeredBy runs the job after the job with vid finished with one of events, by default
+// RUN_SUCCESS.
+func (b *ScheduleBuilder) TriggeredBy(vid string, events ...TriggerEvent) *ScheduleBuilder {
+	if vid == "" {
+		return b.fail(fmt.Errorf("no triggering job"))
+	}
+	if len(events) == 0 {
+		events = []TriggerEvent{TriggerEventRunSuccess}
+	}
+	b.schedule.Type = ScheduleTypeTrigger
+	b.schedule.TriggerItem = vid
+	b.schedule.TriggerEvents = events
+	b.schedule.TriggerRunType = TriggerRunTypeImmediately
+	return b
+}
+
+// Delayed delays a triggered job by n units after the triggering job finished.
+func (b *ScheduleBuilder) Delayed(n int, unit EveryType) *ScheduleBuilder {
+	if n <= 0 {
+		return b.fail(fmt.Errorf("invalid delay %d", n))
+	}
+	if unit == EveryTypeDay || !unit.Valid() {
+		return b.fail(fmt.Errorf("unsupported unit %s", unit))
+	}
+	b.schedule.TriggerRunType = TriggerRunTypeDelayed
+	b.schedule.Every = n
+	b.schedule.EveryType = unit
+	return b
+}
+
+// At sets the start time.
+func (b *ScheduleBuilder) At(hour, minute int) *ScheduleBuilder {
+	clock, err := clockOf(hour, minute)
+	if err != nil {
+		return b.fail(err)
+	}
+	b.schedule.StartTime = formatClock(clock)
+	return b
+}
+
+// Between sets the start and end time of a periodic schedule. An end before the start ends the
+// runs on the next day.
+func (b *ScheduleBuilder) Between(startHour, startMinute, endHour, endMinute int) *ScheduleBuilder {
+	start, err := clockOf(startHour, startMinute)
+	if err != nil {
+		return b.fail(err)
+	}
+	end, err := clockOf(endHour, endMinute)
+	if err != nil {
+		return b.fail(err)
+	}
+	b.schedule.StartTime = formatClock(start)
+	b.schedule.EndTime = formatClock(end)
+	return b
+}
+
+// On selects the days of the week a daily or periodic schedule runs on.
+func (b *ScheduleBuilder) On(days Days) *ScheduleBuilder {
+	if days <= 0 || days > AllDays {
+		return b.fail(fmt.Errorf("invalid days %d", days))
+	}
+	b.schedule.On = int(days)
+	return b
+}
+
+// In sets the time zone of the schedule. Zones the time zone database doesn't resolve by their
+// name, like those of time.FixedZone, are stored by their offset only. The offset of other
+// zones is only set along with an effective date, as the offset at that date.
+func (b *ScheduleBuilder) In(loc *time.Location) *ScheduleBuilder {
+	if loc == nil {
+		return b.fail(fmt.Errorf("no time zone"))
+	}
+	b.loc = loc
+	return b
+}
+
+// EffectiveFrom sets the date the schedule is effective from.
+func (b *ScheduleBuilder) EffectiveFrom(t time.Time) *ScheduleBuilder {
+	b.schedule.EffectiveDate = t.UTC().Format(TimeLayout)
+	return b
+}
+
+// Position sets the priority of the schedule, lower positions take precedence.
+func (b *ScheduleBuilder) Position(position int) *ScheduleBuilder {
+	b.schedule.Position = position
+	return b
+}
+
+// Disabled disables the schedule.
+func (b *ScheduleBuilder) Disabled() *ScheduleBuilder {
+	b.schedule.Enabled = false
+	return b
+}
+
+// Build returns the schedule, or the first error of the builder.
+func (b *ScheduleBuilder) Build() (Schedule, error) {
+	if b.err != nil {
+		return Schedule{}, b.err
+	}
+	if b.schedule.Type == ScheduleTypeNone {
+		return Schedule{}, fmt.Errorf("no schedule rule selected")
+	}
+	schedule := b.schedule
+	if b.loc != nil {
+		effective, err := schedule.EffectiveFrom()
+		if err != nil {
+			return Schedule{}, err
+		}
+		schedule.Timezone, schedule.TimezoneOffsetMs = zoneOf(b.loc, effective)
+	}
+	return schedule, nil
+}
+
+// zoneOf returns the timezone name and offset in ms of a schedule in loc, effective from
+// effective.
+func zoneOf(loc *time.Location, effective time.Time) (name string, offsetMs int) {
+	if !resolvable(loc) {
+		// the offset of a fixed zone is the same at any time
+		_, offset := time.Time{}.In(loc).Zone()
+		return "", offset * 1000
+	}
+	if !effective.IsZero() {
+		_, offset := effective.In(loc).Zone()
+		offsetMs = offset * 1000
+	}
+	return loc.String(), offsetMs
+}
+
+// resolvable reports whether the time zone database resolves the name of loc to a zone with
+// the same offsets. time.FixedZone("CET", 3600) doesn't resolve, as CET observes daylight
+// saving time.
+func resolvable(loc *time.Location) bool {
+	name := loc.String()
+	if name == "" {
+		return false
+	}
+	named, err := time.LoadLocation(name)
+	if err != nil {
+		return false
+	}
+	for year := 1970; year < 2038; year++ {
+		for _, month := range []time.Month{time.January, time.July} {
+			t := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+			_, offset := t.In(loc).Zone()
+			_, namedOffset := t.In(named).Zone()
+			if offset != namedOffset {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (b *ScheduleBuilder) fail(err error) *ScheduleBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+func clockOf(hour, minute int) (time.Duration, error) {
+	if hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return 0, fmt.Errorf("invalid time of day %02d:%02d", hour, minute)
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, nil
+}
+
+// formatClock formats a time since midnight as ClockLayout.
+func formatClock(d time.Duration) string {
+	return time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC).Add(d).Format(ClockLayout)
+}
@@ -0,0 +1,55 @@
+package nakivo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peertechde/go-nakivo"
+)
+
+func TestScheduleBuilderIn(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	summer := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		builder  *nakivo.ScheduleBuilder
+		timezone string
+		offsetMs int
+	}{
+		{"utc", nakivo.NewSchedule().Daily().In(time.UTC), "UTC", 0},
+		{"named", nakivo.NewSchedule().Daily().In(berlin), "Europe/Berlin", 0},
+		{"named effective", nakivo.NewSchedule().Daily().In(berlin).EffectiveFrom(summer), "Europe/Berlin", 2 * 3600 * 1000},
+		{"named effective first", nakivo.NewSchedule().Daily().EffectiveFrom(summer.AddDate(0, 6, 0)).In(berlin), "Europe/Berlin", 3600 * 1000},
+		{"fixed", nakivo.NewSchedule().Daily().In(time.FixedZone("", 5*3600+1800)), "", (5*3600 + 1800) * 1000},
+		{"fixed named like a zone", nakivo.NewSchedule().Daily().In(time.FixedZone("CET", 3600)), "", 3600 * 1000},
+		{"fixed named like utc", nakivo.NewSchedule().Daily().In(time.FixedZone("UTC", 0)), "UTC", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := build(t, tt.builder)
+			if schedule.Timezone != tt.timezone || schedule.TimezoneOffsetMs != tt.offsetMs {
+				t.Errorf("expected time zone %q with offset %d, got %q with offset %d", tt.timezone, tt.offsetMs, schedule.Timezone, schedule.TimezoneOffsetMs)
+			}
+		})
+	}
+
+	if _, err := nakivo.NewSchedule().Daily().In(nil).Build(); err == nil {
+		t.Error("expected an error without time zone")
+	}
+}
+
+func TestScheduleBuilderFixedZone(t *testing.T) {
+	// a fixed zone keeps its offset in summer, unlike the zone of the same name
+	schedule := build(t, nakivo.NewSchedule().Daily().At(22, 0).In(time.FixedZone("CET", 3600)))
+	next, ok, err := schedule.Next(time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC))
+	if err != nil || !ok {
+		t.Fatalf("expected a run, got %t (%v)", ok, err)
+	}
+	if want := time.Date(2024, 7, 1, 21, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Errorf("expected a run at %s, got %s", want, next.UTC())
+	}
+}
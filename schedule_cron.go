@@ -0,0 +1,337 @@
+package nakivo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotRepresentable is returned if a schedule has no equivalent cron expression or a cron
+// expression has no equivalent schedule.
+var ErrNotRepresentable = errors.New("not representable")
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronField describes a field of a cron expression.
+type cronField struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	{name: "day of week", min: 0, max: 7, names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}},
+}
+
+// Cron returns the standard five field cron expression of the schedule, in the time zone of
+// the schedule. Schedules without equivalent expression, like TRIGGER schedules, runs every
+// few seconds or on the last Friday of a month, are reported as ErrNotRepresentable.
+func (schedule *Schedule) Cron() (string, error) {
+	start, ok, err := schedule.StartClock()
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("no start time")
+	}
+	if start%time.Minute != 0 {
+		return "", notRepresentable("start time %s has seconds", schedule.StartTime)
+	}
+	hour, minute := int(start/time.Hour), int(start%time.Hour/time.Minute)
+	if schedule.Type != ScheduleTypeMonthlyYearly && Days(schedule.On)&AllDays == 0 {
+		return "", notRepresentable("runs on no day")
+	}
+
+	switch schedule.Type {
+	case ScheduleTypeDaily:
+		return fmt.Sprintf("%d %d * * %s", minute, hour, cronDays(Days(schedule.On))), nil
+	case ScheduleTypePeriodically:
+		return schedule.periodicCron(start)
+	case ScheduleTypeMonthlyYearly:
+		if schedule.MonthlyEveryType != MonthlyEveryTypeDay {
+			return "", notRepresentable("runs on the %s weekday of a month", strings.ToLower(string(schedule.MonthlyEveryType)))
+		}
+		if schedule.DayOfMonth < 1 || schedule.DayOfMonth > 28 {
+			// the director runs on the last day of shorter months, cron skips them
+			return "", notRepresentable("runs on day %d of a month", schedule.DayOfMonth)
+		}
+		month := "*"
+		if schedule.Month != 0 {
+			month = strconv.Itoa(schedule.Month)
+		}
+		return fmt.Sprintf("%d %d %d %s *", minute, hour, schedule.DayOfMonth, month), nil
+	}
+	return "", notRepresentable("schedule type %s", schedule.Type)
+}
+
+// periodicCron returns the cron expression of a PERIODICALLY schedule. All runs of a day must
+// start before midnight, as planned by Next.
+func (schedule *Schedule) periodicCron(start time.Duration) (string, error) {
+	hour, minute := int(start/time.Hour), int(start%time.Hour/time.Minute)
+	every := schedule.Every
+	if every <= 0 {
+		return "", fmt.Errorf("invalid period %d", every)
+	}
+	if schedule.EveryType == EveryTypeDay {
+		if every != 1 {
+			return "", notRepresentable("runs every %d days", every)
+		}
+		return fmt.Sprintf("%d %d * * *", minute, hour), nil
+	}
+	if schedule.EveryType != EveryTypeHour && schedule.EveryType != EveryTypeMinute {
+		return "", notRepresentable("runs every %d %s", every, strings.ToLower(string(schedule.EveryType)))
+	}
+
+	period, err := schedule.period()
+	if err != nil {
+		return "", err
+	}
+	window, wraps, err := schedule.window(start)
+	if err != nil {
+		return "", err
+	}
+	// the last run of a day
+	last := start + window/period*period
+	if wraps || last >= 24*time.Hour {
+		return "", notRepresentable("runs beyond midnight")
+	}
+
+	days := cronDays(Days(schedule.On))
+	if schedule.EveryType == EveryTypeHour {
+		return fmt.Sprintf("%d %s * * %s", minute, cronRange(hour, int(last/time.Hour), every, 23), days), nil
+	}
+	if 60%every != 0 || minute >= every {
+		return "", notRepresentable("runs every %d minutes from %s", every, schedule.StartTime)
+	}
+	// cron runs in every minute of the last hour, so the schedule must do so too
+	if int(last%time.Hour/time.Minute)+every < 60 {
+		return "", notRepresentable("runs every %d minutes until %s", every, schedule.EndTime)
+	}
+	minutes := fmt.Sprintf("%d/%d", minute, every)
+	if minute == 0 {
+		minutes = "*/" + strconv.Itoa(every)
+	}
+	return fmt.Sprintf("%s %s * * %s", minutes, cronRange(hour, int(last/time.Hour), 1, 23), days), nil
+}
+
+// ScheduleFromCron returns the schedule of a standard five field cron expression or one of the
+// macros like @daily, in UTC. Use the ScheduleBuilder to set another time zone. Expressions
+// without equivalent schedule are reported as ErrNotRepresentable.
+func ScheduleFromCron(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return Schedule{}, fmt.Errorf("invalid cron expression %q (expected %d fields, got %d)", expr, len(cronFields), len(fields))
+	}
+	values := make([][]int, len(fields))
+	for i, field := range fields {
+		v, err := cronFields[i].parse(field)
+		if err != nil {
+			return Schedule{}, fmt.Errorf("invalid cron expression %q (%s)", expr, err)
+		}
+		values[i] = v
+	}
+	minutes, hours, doms, months, dows := values[0], values[1], values[2], values[3], values[4]
+	allDoms, allMonths, allDows := len(doms) == 31, len(months) == 12, len(dows) == 7
+
+	b := NewSchedule()
+	if !allDoms {
+		// a day of month runs monthly or yearly
+		if len(doms) != 1 || !allDows || (len(months) != 1 && !allMonths) || len(minutes) != 1 || len(hours) != 1 {
+			return Schedule{}, notRepresentable("cron expression %q", expr)
+		}
+		if doms[0] > 28 {
+			// cron skips shorter months, the director runs on their last day
+			return Schedule{}, notRepresentable("cron expression %q runs on day %d of a month", expr, doms[0])
+		}
+		b.MonthlyOnDay(doms[0]).At(hours[0], minutes[0])
+		if !allMonths {
+			b.InMonth(time.Month(months[0]))
+		}
+		return b.Build()
+	}
+	if !allMonths {
+		return Schedule{}, notRepresentable("cron expression %q restricts months", expr)
+	}
+
+	var days Days
+	for _, dow := range dows {
+		days |= DaysOf(time.Weekday(dow))
+	}
+	b.On(days)
+
+	switch {
+	case len(minutes) == 1 && len(hours) == 1:
+		b.Daily().At(hours[0], minutes[0])
+	case len(minutes) == 1:
+		step, ok := progression(hours)
+		if !ok {
+			return Schedule{}, notRepresentable("cron expression %q", expr)
+		}
+		b.Every(step, EveryTypeHour).Between(hours[0], minutes[0], hours[len(hours)-1], minutes[0])
+	default:
+		step, ok := progression(minutes)
+		if !ok || 60%step != 0 || minutes[0] >= step || len(minutes) != 60/step {
+			return Schedule{}, notRepresentable("cron expression %q", expr)
+		}
+		if _, ok := progression(hours); !ok || (len(hours) > 1 && hours[1]-hours[0] != 1) {
+			return Schedule{}, notRepresentable("cron expression %q", expr)
+		}
+		b.Every(step, EveryTypeMinute).Between(hours[0], minutes[0], hours[len(hours)-1], minutes[len(minutes)-1])
+	}
+	return b.Build()
+}
+
+// parse returns the sorted values selected by a field of a cron expression.
+func (f cronField) parse(field string) ([]int, error) {
+	selected := make(map[int]bool)
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			rng = part[:i]
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid step %q in %s", part[i+1:], f.name)
+			}
+		}
+		lo, hi := f.min, f.max
+		switch {
+		case rng == "*":
+			if f.name == "day of week" {
+				hi = 6
+			}
+		case strings.Contains(rng, "-"):
+			bounds := strings.SplitN(rng, "-", 2)
+			var err error
+			if lo, err = f.value(bounds[0]); err != nil {
+				return nil, err
+			}
+			if hi, err = f.value(bounds[1]); err != nil {
+				return nil, err
+			}
+			if hi < lo {
+				return nil, fmt.Errorf("invalid range %q in %s", rng, f.name)
+			}
+		default:
+			v, err := f.value(rng)
+			if err != nil {
+				return nil, err
+			}
+			lo = v
+			if step == 1 {
+				hi = v
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			if f.name == "day of week" {
+				v := v % 7
+				selected[v] = true
+				continue
+			}
+			selected[v] = true
+		}
+	}
+	values := make([]int, 0, len(selected))
+	for v := range selected {
+		values = append(values, v)
+	}
+	sort.Ints(values)
+	return values, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return i + f.min, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s %q", f.name, s)
+	}
+	return v, nil
+}
+
+// cronDays formats days as the day of week field of a cron expression.
+func cronDays(days Days) string {
+	if days&AllDays == AllDays {
+		return "*"
+	}
+	var selected []int
+	for dow := 0; dow < 7; dow++ {
+		if days.Has(time.Weekday(dow)) {
+			selected = append(selected, dow)
+		}
+	}
+	var parts []string
+	for i := 0; i < len(selected); {
+		j := i
+		for j+1 < len(selected) && selected[j+1] == selected[j]+1 {
+			j++
+		}
+		switch {
+		case j-i >= 2:
+			parts = append(parts, fmt.Sprintf("%d-%d", selected[i], selected[j]))
+		case j-i == 1:
+			parts = append(parts, strconv.Itoa(selected[i]), strconv.Itoa(selected[j]))
+		default:
+			parts = append(parts, strconv.Itoa(selected[i]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+// cronRange formats the values from first to last in steps as a field of a cron expression,
+// whose values range from 0 to max.
+func cronRange(first, last, step, max int) string {
+	var rng string
+	switch {
+	case first == 0 && last+step > max:
+		rng = "*"
+	case first == last:
+		return strconv.Itoa(first)
+	default:
+		rng = fmt.Sprintf("%d-%d", first, last)
+	}
+	if step > 1 {
+		rng += "/" + strconv.Itoa(step)
+	}
+	return rng
+}
+
+// progression returns the step of values if they form an arithmetic progression.
+func progression(values []int) (int, bool) {
+	if len(values) < 2 {
+		return 1, len(values) == 1
+	}
+	step := values[1] - values[0]
+	for i := 2; i < len(values); i++ {
+		if values[i]-values[i-1] != step {
+			return 0, false
+		}
+	}
+	return step, true
+}
+
+func notRepresentable(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrNotRepresentable, fmt.Sprintf(format, args...))
+}
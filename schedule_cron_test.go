@@ -0,0 +1,197 @@
+package nakivo_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/peertechde/go-nakivo"
+)
+
+// cronMatches reports whether the five field cron expression matches at. Only lists, ranges,
+// steps and wildcards are supported, and day of month and day of week must match both.
+func cronMatches(t *testing.T, expr string, at time.Time) bool {
+	t.Helper()
+	bounds := [][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 6}}
+	values := []int{at.Minute(), at.Hour(), at.Day(), int(at.Month()), int(at.Weekday())}
+	for i, field := range strings.Fields(expr) {
+		matched := false
+		for _, part := range strings.Split(field, ",") {
+			rng, step := part, 1
+			if before, after, ok := strings.Cut(part, "/"); ok {
+				rng, step = before, atoi(t, after)
+			}
+			lo, hi := bounds[i][0], bounds[i][1]
+			if first, last, ok := strings.Cut(rng, "-"); ok {
+				lo, hi = atoi(t, first), atoi(t, last)
+			} else if rng != "*" {
+				lo = atoi(t, rng)
+				if step == 1 {
+					hi = lo
+				}
+			}
+			for v := lo; v <= hi; v += step {
+				matched = matched || v == values[i]
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func atoi(t *testing.T, s string) int {
+	t.Helper()
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func build(t *testing.T, b *nakivo.ScheduleBuilder) nakivo.Schedule {
+	t.Helper()
+	schedule, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schedule
+}
+
+func TestScheduleCron(t *testing.T) {
+	tests := []struct {
+		schedule *nakivo.ScheduleBuilder
+		want     string
+	}{
+		{schedule: nakivo.NewSchedule().Daily().At(22, 0).On(nakivo.Weekdays), want: "0 22 * * 1-5"},
+		{schedule: nakivo.NewSchedule().Daily().At(7, 30).On(nakivo.Monday | nakivo.Wednesday | nakivo.Sunday), want: "30 7 * * 0,1,3"},
+		{schedule: nakivo.NewSchedule().Every(4, nakivo.EveryTypeHour).Between(6, 0, 22, 0), want: "0 6-22/4 * * *"},
+		{schedule: nakivo.NewSchedule().Every(4, nakivo.EveryTypeHour).Between(6, 0, 21, 0).On(nakivo.Weekend), want: "0 6-18/4 * * 0,6"},
+		{schedule: nakivo.NewSchedule().Every(4, nakivo.EveryTypeHour).At(0, 0), want: "0 */4 * * *"},
+		{schedule: nakivo.NewSchedule().Every(1, nakivo.EveryTypeHour).At(0, 30), want: "30 * * * *"},
+		{schedule: nakivo.NewSchedule().Every(15, nakivo.EveryTypeMinute).Between(8, 0, 17, 45), want: "*/15 8-17 * * *"},
+		{schedule: nakivo.NewSchedule().Every(20, nakivo.EveryTypeMinute).Between(8, 10, 17, 50), want: "10/20 8-17 * * *"},
+		{schedule: nakivo.NewSchedule().Every(1, nakivo.EveryTypeDay).At(3, 0), want: "0 3 * * *"},
+		{schedule: nakivo.NewSchedule().MonthlyOnDay(15).At(1, 30), want: "30 1 15 * *"},
+		{schedule: nakivo.NewSchedule().MonthlyOnDay(1).InMonth(time.June).At(2, 0), want: "0 2 1 6 *"},
+	}
+	// March 4th 2024 is a Monday
+	from := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 35)
+	for _, tt := range tests {
+		schedule := build(t, tt.schedule)
+		got, err := schedule.Cron()
+		if err != nil {
+			t.Errorf("Cron() of %q failed (%s)", tt.want, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, got)
+			continue
+		}
+
+		// the expression matches the runs planned by Next, minute by minute
+		next, ok, err := schedule.Next(from.Add(-time.Nanosecond))
+		if err != nil || !ok {
+			t.Fatalf("Next of %q failed (%v)", tt.want, err)
+		}
+		for at := from; at.Before(to); at = at.Add(time.Minute) {
+			planned := at.Equal(next)
+			if planned {
+				if next, _, err = schedule.Next(at); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if matched := cronMatches(t, got, at); matched != planned {
+				t.Errorf("%q matches %s: %t, planned: %t", got, at, matched, planned)
+				break
+			}
+		}
+	}
+}
+
+func TestScheduleCronNotRepresentable(t *testing.T) {
+	seconds := build(t, nakivo.NewSchedule().Daily())
+	seconds.StartTime = "01:00:30 AM"
+
+	tests := []struct {
+		name     string
+		schedule nakivo.Schedule
+	}{
+		// the runs of a day continue after midnight until 02:00
+		{name: "periodically without end", schedule: build(t, nakivo.NewSchedule().Every(4, nakivo.EveryTypeHour).At(6, 0))},
+		{name: "periodically with end at start", schedule: build(t, nakivo.NewSchedule().Every(4, nakivo.EveryTypeHour).Between(6, 0, 6, 0))},
+		{name: "periodically beyond midnight", schedule: build(t, nakivo.NewSchedule().Every(4, nakivo.EveryTypeHour).Between(22, 0, 1, 0))},
+		{name: "seconds", schedule: build(t, nakivo.NewSchedule().Every(30, nakivo.EveryTypeSecond))},
+		{name: "every other day", schedule: build(t, nakivo.NewSchedule().Every(2, nakivo.EveryTypeDay))},
+		{name: "uneven minutes", schedule: build(t, nakivo.NewSchedule().Every(7, nakivo.EveryTypeMinute))},
+		{name: "incomplete last hour", schedule: build(t, nakivo.NewSchedule().Every(15, nakivo.EveryTypeMinute).Between(8, 0, 17, 0))},
+		{name: "last friday", schedule: build(t, nakivo.NewSchedule().Monthly(nakivo.MonthlyEveryTypeLast, time.Friday))},
+		{name: "last day of month", schedule: build(t, nakivo.NewSchedule().MonthlyOnDay(31))},
+		{name: "trigger", schedule: build(t, nakivo.NewSchedule().TriggeredBy("Job-1"))},
+		{name: "start with seconds", schedule: seconds},
+	}
+	for _, tt := range tests {
+		if expr, err := tt.schedule.Cron(); !errors.Is(err, nakivo.ErrNotRepresentable) {
+			t.Errorf("%s: expected ErrNotRepresentable, got %q (%v)", tt.name, expr, err)
+		}
+	}
+}
+
+func TestScheduleFromCron(t *testing.T) {
+	tests := []struct {
+		expr string
+		want *nakivo.ScheduleBuilder
+	}{
+		{expr: "0 22 * * 1-5", want: nakivo.NewSchedule().Daily().At(22, 0).On(nakivo.Weekdays)},
+		{expr: "0 22 * * MON-FRI", want: nakivo.NewSchedule().Daily().At(22, 0).On(nakivo.Weekdays)},
+		{expr: "0 6-22/4 * * *", want: nakivo.NewSchedule().Every(4, nakivo.EveryTypeHour).Between(6, 0, 22, 0)},
+		{expr: "*/15 8-17 * * *", want: nakivo.NewSchedule().Every(15, nakivo.EveryTypeMinute).Between(8, 0, 17, 45)},
+		{expr: "30 1 15 * *", want: nakivo.NewSchedule().MonthlyOnDay(15).At(1, 30)},
+		{expr: "0 2 1 6 *", want: nakivo.NewSchedule().MonthlyOnDay(1).InMonth(time.June).At(2, 0)},
+		{expr: "@daily", want: nakivo.NewSchedule().Daily().At(0, 0)},
+		{expr: "@weekly", want: nakivo.NewSchedule().Daily().At(0, 0).On(nakivo.Sunday)},
+		{expr: "0 3 * * 7", want: nakivo.NewSchedule().Daily().At(3, 0).On(nakivo.Sunday)},
+	}
+	for _, tt := range tests {
+		got, err := nakivo.ScheduleFromCron(tt.expr)
+		if err != nil {
+			t.Errorf("ScheduleFromCron(%q) failed (%s)", tt.expr, err)
+			continue
+		}
+		if want := build(t, tt.want); !equalSchedules(got, want) {
+			t.Errorf("ScheduleFromCron(%q) = %+v, want %+v", tt.expr, got, want)
+		}
+	}
+}
+
+func TestScheduleFromCronNotRepresentable(t *testing.T) {
+	for _, expr := range []string{
+		"0 0 31 * *",
+		"0 0 29 2 *",
+		"0 0 1-7 * 5",
+		"0 0 * 1 *",
+		"0 0 1 1,6 *",
+		"5,7 * * * *",
+		"0 1,5,6 * * *",
+		"0-10 * * * *",
+	} {
+		if _, err := nakivo.ScheduleFromCron(expr); !errors.Is(err, nakivo.ErrNotRepresentable) {
+			t.Errorf("ScheduleFromCron(%q): expected ErrNotRepresentable, got %v", expr, err)
+		}
+	}
+	for _, expr := range []string{"0 0 * *", "61 * * * *", "0 0 * * MON-", "*/0 * * * *"} {
+		if _, err := nakivo.ScheduleFromCron(expr); err == nil || errors.Is(err, nakivo.ErrNotRepresentable) {
+			t.Errorf("ScheduleFromCron(%q): expected an invalid expression, got %v", expr, err)
+		}
+	}
+}
+
+func equalSchedules(a, b nakivo.Schedule) bool {
+	return a.Type == b.Type && a.StartTime == b.StartTime && a.EndTime == b.EndTime && a.On == b.On &&
+		a.Every == b.Every && a.EveryType == b.EveryType && a.MonthlyEveryType == b.MonthlyEveryType &&
+		a.DayOfMonth == b.DayOfMonth && a.Month == b.Month && a.Timezone == b.Timezone
+}
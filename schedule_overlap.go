@@ -0,0 +1,263 @@
+package nakivo
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	// defaultRunDuration is assumed for jobs without average duration.
+	defaultRunDuration = time.Hour
+
+	// maxPlannedRuns bounds the runs planned per job by Overlaps.
+	maxPlannedRuns = 1 << 16
+)
+
+// OverlapKind is the type of an overlap of job runs.
+type OverlapKind string
+
+const (
+	// TransporterOverloaded is reported when more jobs run on a transporter than its maximum
+	// load allows
+	TransporterOverloaded OverlapKind = "TRANSPORTER_OVERLOADED"
+
+	// StorageShared is reported when several jobs write to the same storage at the same time
+	StorageShared OverlapKind = "STORAGE_SHARED"
+)
+
+// OverlapOptions controls the prediction of job runs by Overlaps.
+type OverlapOptions struct {
+	// Duration assumed for jobs without average duration. Defaults to an hour.
+	DefaultDuration time.Duration
+}
+
+// RunWindow is a planned run and its predicted end.
+type RunWindow struct {
+	PlannedRun
+
+	// End the run is predicted to finish at, after the average duration of the job
+	End time.Time
+}
+
+// Overlap is a period in which planned job runs compete for a transporter or storage.
+type Overlap struct {
+	// Kind of the overlap
+	Kind OverlapKind
+
+	// Start and End of the period
+	Start time.Time
+	End   time.Time
+
+	// Transporter which is overloaded, only set for TransporterOverloaded
+	Transporter *Transporter
+
+	// Storage which is shared, only set for StorageShared
+	Storage *Storage
+
+	// Peak number of jobs running at the same time during the period
+	Load int
+
+	// Runs of the jobs running during the period
+	Runs []RunWindow
+}
+
+// jobSpan is a period in which a job is predicted to run, joining overlapping runs of the job.
+type jobSpan struct {
+	job        *Job
+	start, end time.Time
+	runs       []RunWindow
+}
+
+// Overlaps predicts the runs of the jobs of the planner between from and to, and reports the
+// periods in which more jobs run on a transporter than its MaxLoadFactor allows, or in which
+// several jobs write to the same storage. Runs last for the average duration of their job.
+func (p *Planner) Overlaps(from, to time.Time, opts *OverlapOptions) ([]Overlap, error) {
+	if opts == nil {
+		opts = &OverlapOptions{}
+	}
+	fallback := opts.DefaultDuration
+	if fallback <= 0 {
+		fallback = defaultRunDuration
+	}
+
+	var (
+		transporters = make(map[string]*Transporter)
+		storages     = make(map[string]*Storage)
+		byTransport  = make(map[string][]*jobSpan)
+		byStorage    = make(map[string][]*jobSpan)
+
+		// vids of the transporters and storages in the order they were seen
+		transporterVids, storageVids []string
+	)
+	for _, job := range p.order {
+		duration := job.AverageDuration()
+		if duration <= 0 {
+			duration = fallback
+		}
+		// runs started before from may still be running
+		runs, err := p.runsBetween(job, from.Add(-duration), to)
+		if err != nil {
+			return nil, err
+		}
+		spans := joinRuns(job, runs, duration)
+		if len(spans) == 0 {
+			continue
+		}
+
+		// a job counts once per transporter and storage
+		seenTransporters, seenStorages := make(map[string]bool), make(map[string]bool)
+		for i := range job.Transporters {
+			t := &job.Transporters[i]
+			if seenTransporters[t.Vid] {
+				continue
+			}
+			seenTransporters[t.Vid] = true
+			if _, ok := transporters[t.Vid]; !ok {
+				transporters[t.Vid] = t
+				transporterVids = append(transporterVids, t.Vid)
+			}
+			byTransport[t.Vid] = append(byTransport[t.Vid], spans...)
+		}
+		for i := range job.Storages {
+			s := &job.Storages[i]
+			if seenStorages[s.Vid] {
+				continue
+			}
+			seenStorages[s.Vid] = true
+			if _, ok := storages[s.Vid]; !ok {
+				storages[s.Vid] = s
+				storageVids = append(storageVids, s.Vid)
+			}
+			byStorage[s.Vid] = append(byStorage[s.Vid], spans...)
+		}
+	}
+
+	var overlaps []Overlap
+	for _, vid := range transporterVids {
+		t := transporters[vid]
+		if t.MaxLoadFactor <= 0 {
+			continue
+		}
+		for _, o := range sweep(byTransport[vid], t.MaxLoadFactor, from, to) {
+			o.Kind, o.Transporter = TransporterOverloaded, t
+			overlaps = append(overlaps, o)
+		}
+	}
+	for _, vid := range storageVids {
+		for _, o := range sweep(byStorage[vid], 1, from, to) {
+			o.Kind, o.Storage = StorageShared, storages[vid]
+			overlaps = append(overlaps, o)
+		}
+	}
+	sort.SliceStable(overlaps, func(i, j int) bool {
+		return overlaps[i].Start.Before(overlaps[j].Start)
+	})
+	return overlaps, nil
+}
+
+// runsBetween returns the planned runs of job starting between from and to.
+func (p *Planner) runsBetween(job *Job, from, to time.Time) ([]PlannedRun, error) {
+	runs, err := p.nextRuns(job, from, to, maxPlannedRuns+1, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) > maxPlannedRuns {
+		return nil, fmt.Errorf("job %s runs more than %d times", job.Name, maxPlannedRuns)
+	}
+	return runs, nil
+}
+
+// joinRuns joins the overlapping runs of job, as a job doesn't run twice at the same time.
+func joinRuns(job *Job, runs []PlannedRun, duration time.Duration) []*jobSpan {
+	var spans []*jobSpan
+	for _, run := range runs {
+		window := RunWindow{PlannedRun: run, End: run.Time.Add(duration)}
+		if n := len(spans); n > 0 && run.Time.Before(spans[n-1].end) {
+			last := spans[n-1]
+			if window.End.After(last.end) {
+				last.end = window.End
+			}
+			last.runs = append(last.runs, window)
+			continue
+		}
+		spans = append(spans, &jobSpan{job: job, start: run.Time, end: window.End, runs: []RunWindow{window}})
+	}
+	return spans
+}
+
+// sweep returns the periods between from and to in which more than limit of spans overlap.
+func sweep(spans []*jobSpan, limit int, from, to time.Time) []Overlap {
+	type event struct {
+		at    time.Time
+		start bool
+		span  *jobSpan
+	}
+	events := make([]event, 0, 2*len(spans))
+	for _, span := range spans {
+		events = append(events, event{at: span.start, start: true, span: span}, event{at: span.end, span: span})
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].at.Equal(events[j].at) {
+			return events[i].at.Before(events[j].at)
+		}
+		// a run ending when another starts doesn't overlap with it
+		return !events[i].start && events[j].start
+	})
+
+	var (
+		overlaps []Overlap
+		current  *Overlap
+		involved map[*jobSpan]bool
+		active   = make(map[*jobSpan]bool)
+	)
+	for _, e := range events {
+		if e.start {
+			active[e.span] = true
+		} else {
+			delete(active, e.span)
+		}
+
+		switch {
+		case len(active) > limit && current == nil:
+			current = &Overlap{Start: e.at}
+			involved = make(map[*jobSpan]bool)
+			fallthrough
+		case len(active) > limit:
+			for span := range active {
+				involved[span] = true
+			}
+			if len(active) > current.Load {
+				current.Load = len(active)
+			}
+		case current != nil:
+			current.End = e.at
+			if current.End.After(from) && current.Start.Before(to) {
+				current.Runs = involvedRuns(involved, current.Start, current.End)
+				overlaps = append(overlaps, *current)
+			}
+			current = nil
+		}
+	}
+	return overlaps
+}
+
+// involvedRuns returns the runs of spans overlapping the period from start to end, ordered by
+// their start.
+func involvedRuns(spans map[*jobSpan]bool, start, end time.Time) []RunWindow {
+	var runs []RunWindow
+	for span := range spans {
+		for _, run := range span.runs {
+			if run.Time.Before(end) && run.End.After(start) {
+				runs = append(runs, run)
+			}
+		}
+	}
+	sort.SliceStable(runs, func(i, j int) bool {
+		if !runs[i].Time.Equal(runs[j].Time) {
+			return runs[i].Time.Before(runs[j].Time)
+		}
+		return runs[i].Job.Name < runs[j].Job.Name
+	})
+	return runs
+}
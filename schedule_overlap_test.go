@@ -0,0 +1,208 @@
+package nakivo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peertechde/go-nakivo"
+)
+
+// overlapJob returns a job running daily at hour and minute with the average duration avg, writing to
+// storages through transporters.
+func overlapJob(t *testing.T, name string, hour, minute int, avg time.Duration, transporters []nakivo.Transporter, storages ...string) nakivo.Job {
+	t.Helper()
+	job := nakivo.Job{
+		Vid:               "Job-" + name,
+		Name:              name,
+		IsEnabled:         true,
+		AverageDurationMs: avg.Milliseconds(),
+		Schedules:         []nakivo.Schedule{build(t, nakivo.NewSchedule().Daily().At(hour, minute))},
+		Transporters:      transporters,
+	}
+	for _, storage := range storages {
+		job.Storages = append(job.Storages, nakivo.Storage{Vid: storage, Name: storage})
+	}
+	return job
+}
+
+// assertOverlap checks the period, load and runs of overlap. runs are the job names and start
+// times of the runs, e.g. "a 22:00".
+func assertOverlap(t *testing.T, overlap nakivo.Overlap, kind nakivo.OverlapKind, start, end time.Time, load int, runs ...string) {
+	t.Helper()
+	if overlap.Kind != kind || !overlap.Start.Equal(start) || !overlap.End.Equal(end) || overlap.Load != load {
+		t.Errorf("expected %s from %s to %s with load %d, got %s from %s to %s with load %d",
+			kind, start, end, load, overlap.Kind, overlap.Start.UTC(), overlap.End.UTC(), overlap.Load)
+	}
+	var got []string
+	for _, run := range overlap.Runs {
+		got = append(got, run.Job.Name+" "+run.Time.UTC().Format("15:04"))
+	}
+	if len(got) != len(runs) {
+		t.Fatalf("expected runs %v, got %v", runs, got)
+	}
+	for i := range runs {
+		if got[i] != runs[i] {
+			t.Errorf("expected runs %v, got %v", runs, got)
+			break
+		}
+	}
+}
+
+func TestOverlapsTransporterOverloaded(t *testing.T) {
+	// unlimited is not limited by a MaxLoadFactor, so it's never overloaded
+	transporters := []nakivo.Transporter{{Vid: "t1", MaxLoadFactor: 2}, {Vid: "unlimited"}}
+	jobs := []nakivo.Job{
+		overlapJob(t, "a", 22, 0, 2*time.Hour, transporters),
+		overlapJob(t, "b", 23, 0, time.Hour, transporters),
+		overlapJob(t, "c", 23, 30, time.Hour, transporters),
+	}
+	overlaps, err := nakivo.NewPlanner(jobs).Overlaps(utc(1, 12, 0), utc(2, 12, 0), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(overlaps) != 1 {
+		t.Fatalf("expected a single overlap, got %+v", overlaps)
+	}
+	assertOverlap(t, overlaps[0], nakivo.TransporterOverloaded, utc(1, 23, 30), utc(2, 0, 0), 3, "a 22:00", "b 23:00", "c 23:30")
+	if overlaps[0].Transporter == nil || overlaps[0].Transporter.Vid != "t1" || overlaps[0].Storage != nil {
+		t.Errorf("expected transporter t1, got %+v", overlaps[0].Transporter)
+	}
+}
+
+func TestOverlapsStorageShared(t *testing.T) {
+	jobs := []nakivo.Job{
+		overlapJob(t, "a", 22, 0, 2*time.Hour, nil, "s1"),
+		overlapJob(t, "b", 23, 0, 30*time.Minute, nil, "s1"),
+		// starts when a ends
+		overlapJob(t, "c", 0, 0, time.Hour, nil, "s1"),
+		// a job counts once per storage
+		overlapJob(t, "d", 22, 0, time.Hour, nil, "s2", "s2"),
+	}
+	overlaps, err := nakivo.NewPlanner(jobs).Overlaps(utc(1, 12, 0), utc(2, 12, 0), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(overlaps) != 1 {
+		t.Fatalf("expected a single overlap, got %+v", overlaps)
+	}
+	assertOverlap(t, overlaps[0], nakivo.StorageShared, utc(1, 23, 0), utc(1, 23, 30), 2, "a 22:00", "b 23:00")
+	if overlaps[0].Storage == nil || overlaps[0].Storage.Vid != "s1" || overlaps[0].Transporter != nil {
+		t.Errorf("expected storage s1, got %+v", overlaps[0].Storage)
+	}
+}
+
+func TestOverlapsDefaultDuration(t *testing.T) {
+	// jobs without average duration
+	jobs := []nakivo.Job{
+		overlapJob(t, "a", 22, 0, 0, nil, "s1"),
+		overlapJob(t, "b", 23, 0, 0, nil, "s1"),
+	}
+	planner := nakivo.NewPlanner(jobs)
+
+	// a ends at 23:00 after the default of an hour
+	overlaps, err := planner.Overlaps(utc(1, 12, 0), utc(2, 12, 0), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(overlaps) != 0 {
+		t.Errorf("expected no overlaps, got %+v", overlaps)
+	}
+
+	overlaps, err = planner.Overlaps(utc(1, 12, 0), utc(2, 12, 0), &nakivo.OverlapOptions{DefaultDuration: 90 * time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(overlaps) != 1 {
+		t.Fatalf("expected a single overlap, got %+v", overlaps)
+	}
+	assertOverlap(t, overlaps[0], nakivo.StorageShared, utc(1, 23, 0), utc(1, 23, 30), 2, "a 22:00", "b 23:00")
+}
+
+func TestOverlapsJoinedRuns(t *testing.T) {
+	// the runs of a at 22:00, 22:30 and 23:00 overlap each other, but a job doesn't compete
+	// with itself
+	a := overlapJob(t, "a", 22, 0, time.Hour, nil, "s1")
+	a.Schedules = []nakivo.Schedule{build(t, nakivo.NewSchedule().Every(30, nakivo.EveryTypeMinute).Between(22, 0, 23, 0))}
+	jobs := []nakivo.Job{a}
+	overlaps, err := nakivo.NewPlanner(jobs).Overlaps(utc(1, 12, 0), utc(2, 12, 0), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(overlaps) != 0 {
+		t.Errorf("expected no overlaps, got %+v", overlaps)
+	}
+
+	jobs = append(jobs, overlapJob(t, "b", 23, 30, 30*time.Minute, nil, "s1"))
+	overlaps, err = nakivo.NewPlanner(jobs).Overlaps(utc(1, 12, 0), utc(2, 12, 0), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(overlaps) != 1 {
+		t.Fatalf("expected a single overlap, got %+v", overlaps)
+	}
+	// the run at 22:30 ends when b starts
+	assertOverlap(t, overlaps[0], nakivo.StorageShared, utc(1, 23, 30), utc(2, 0, 0), 2, "a 23:00", "b 23:30")
+}
+
+func TestOverlapsBounds(t *testing.T) {
+	jobs := []nakivo.Job{
+		overlapJob(t, "a", 22, 0, 2*time.Hour, nil, "s1"),
+		overlapJob(t, "b", 23, 0, 30*time.Minute, nil, "s1"),
+	}
+	planner := nakivo.NewPlanner(jobs)
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     int
+	}{
+		// a started before from and is still running
+		{name: "ongoing", from: utc(1, 23, 15), to: utc(1, 23, 20), want: 1},
+		{name: "ended at from", from: utc(1, 23, 30), to: utc(2, 12, 0), want: 0},
+		{name: "starts at to", from: utc(1, 12, 0), to: utc(1, 23, 0), want: 0},
+		{name: "every day", from: utc(1, 12, 0), to: utc(4, 12, 0), want: 3},
+	}
+	for _, tt := range tests {
+		overlaps, err := planner.Overlaps(tt.from, tt.to, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(overlaps) != tt.want {
+			t.Errorf("%s: expected %d overlaps, got %+v", tt.name, tt.want, overlaps)
+			continue
+		}
+		if tt.want > 0 {
+			// the full period is reported, not only the part between from and to
+			assertOverlap(t, overlaps[0], nakivo.StorageShared, utc(1, 23, 0), utc(1, 23, 30), 2, "a 22:00", "b 23:00")
+		}
+	}
+}
+
+func TestOverlapsPeriodic(t *testing.T) {
+	a := overlapJob(t, "a", 0, 0, 5*time.Minute, nil, "s1")
+	a.Schedules[0] = build(t, nakivo.NewSchedule().Every(10, nakivo.EveryTypeMinute).Between(22, 0, 23, 0))
+	jobs := []nakivo.Job{a, overlapJob(t, "b", 22, 33, 4*time.Minute, nil, "s1")}
+
+	overlaps, err := nakivo.NewPlanner(jobs).Overlaps(utc(1, 12, 0), utc(2, 12, 0), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(overlaps) != 1 {
+		t.Fatalf("expected a single overlap, got %+v", overlaps)
+	}
+	assertOverlap(t, overlaps[0], nakivo.StorageShared, utc(1, 22, 33), utc(1, 22, 35), 2, "a 22:30", "b 22:33")
+}
+
+func TestOverlapsTooManyRuns(t *testing.T) {
+	job := overlapJob(t, "a", 0, 0, time.Second, nil, "s1")
+
+	// a run every ten seconds for a week stays below the limit, a run every second doesn't
+	job.Schedules[0] = build(t, nakivo.NewSchedule().Every(10, nakivo.EveryTypeSecond))
+	if _, err := nakivo.NewPlanner([]nakivo.Job{job}).Overlaps(utc(1, 0, 0), utc(8, 0, 0), nil); err != nil {
+		t.Fatal(err)
+	}
+	job.Schedules[0] = build(t, nakivo.NewSchedule().Every(1, nakivo.EveryTypeSecond))
+	if _, err := nakivo.NewPlanner([]nakivo.Job{job}).Overlaps(utc(1, 0, 0), utc(8, 0, 0), nil); err == nil {
+		t.Error("expected an error for too many runs")
+	}
+}
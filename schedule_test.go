@@ -0,0 +1,243 @@
+package nakivo_test
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/peertechde/go-nakivo"
+)
+
+// utc returns the time of day on the day of March 2024 in UTC.
+func utc(day, hour, minute int) time.Time {
+	return time.Date(2024, 3, day, hour, minute, 0, 0, time.UTC)
+}
+
+// nextTimes returns the next n runs of schedule after from.
+func nextTimes(t *testing.T, schedule nakivo.Schedule, from time.Time, n int) []time.Time {
+	t.Helper()
+	var times []time.Time
+	for len(times) < n {
+		next, ok, err := schedule.Next(from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		times = append(times, next)
+		from = next
+	}
+	return times
+}
+
+func assertTimes(t *testing.T, got, want []time.Time) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d runs %v, got %d runs %v", len(want), want, len(got), got)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("expected run %d at %s, got %s", i, want[i], got[i].UTC())
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// March 1st 2024 is a Friday
+	from := utc(1, 12, 0)
+
+	tests := []struct {
+		name     string
+		schedule nakivo.Schedule
+		from     time.Time
+		want     []time.Time
+	}{
+		{
+			name:     "daily on weekdays",
+			schedule: nakivo.Schedule{Type: nakivo.ScheduleTypeDaily, StartTime: "10:00:00 PM", On: int(nakivo.Weekdays)},
+			want:     []time.Time{utc(1, 22, 0), utc(4, 22, 0), utc(5, 22, 0)},
+		},
+		{
+			name:     "daily across daylight saving time",
+			schedule: nakivo.Schedule{Type: nakivo.ScheduleTypeDaily, StartTime: "06:00:00 PM", On: int(nakivo.AllDays), Timezone: "Europe/Berlin"},
+			from:     utc(29, 12, 0),
+			want:     []time.Time{time.Date(2024, 3, 29, 18, 0, 0, 0, berlin), time.Date(2024, 3, 30, 18, 0, 0, 0, berlin), time.Date(2024, 3, 31, 18, 0, 0, 0, berlin)},
+		},
+		{
+			name:     "periodically beyond midnight",
+			schedule: nakivo.Schedule{Type: nakivo.ScheduleTypePeriodically, StartTime: "11:00:00 PM", EndTime: "01:00:00 AM", Every: 30, EveryType: nakivo.EveryTypeMinute, On: int(nakivo.AllDays)},
+			want:     []time.Time{utc(1, 23, 0), utc(1, 23, 30), utc(2, 0, 0), utc(2, 0, 30), utc(2, 1, 0), utc(2, 23, 0)},
+		},
+		{
+			name:     "periodically without end",
+			schedule: nakivo.Schedule{Type: nakivo.ScheduleTypePeriodically, StartTime: "06:00:00 AM", Every: 4, EveryType: nakivo.EveryTypeHour, On: int(nakivo.AllDays)},
+			from:     utc(1, 0, 0),
+			// the runs of a day continue until the start time of the next day
+			want: []time.Time{utc(1, 2, 0), utc(1, 6, 0), utc(1, 10, 0), utc(1, 14, 0), utc(1, 18, 0), utc(1, 22, 0), utc(2, 2, 0)},
+		},
+		{
+			name:     "periodically with end at start",
+			schedule: nakivo.Schedule{Type: nakivo.ScheduleTypePeriodically, StartTime: "06:00:00 AM", EndTime: "06:00:00 AM", Every: 8, EveryType: nakivo.EveryTypeHour, On: int(nakivo.Friday)},
+			from:     utc(1, 0, 0),
+			want:     []time.Time{utc(1, 6, 0), utc(1, 14, 0), utc(1, 22, 0), utc(2, 6, 0), utc(8, 6, 0)},
+		},
+		{
+			name:     "every three days",
+			schedule: nakivo.Schedule{Type: nakivo.ScheduleTypePeriodically, StartTime: "01:00:00 AM", Every: 3, EveryType: nakivo.EveryTypeDay, EffectiveDate: "2024-03-02T00:00:00.000Z"},
+			want:     []time.Time{utc(2, 1, 0), utc(5, 1, 0), utc(8, 1, 0)},
+		},
+		{
+			name:     "every three days from the next run",
+			schedule: nakivo.Schedule{Type: nakivo.ScheduleTypePeriodically, StartTime: "01:00:00 AM", Every: 3, EveryType: nakivo.EveryTypeDay, NextRun: "2024-03-06T01:00:00.000Z"},
+			want:     []time.Time{utc(3, 1, 0), utc(6, 1, 0), utc(9, 1, 0)},
+		},
+		{
+			name:     "last friday of the month",
+			schedule: nakivo.Schedule{Type: nakivo.ScheduleTypeMonthlyYearly, StartTime: "05:00:00 PM", MonthlyEveryType: nakivo.MonthlyEveryTypeLast, DayOfWeek: 5},
+			want:     []time.Time{utc(29, 17, 0), time.Date(2024, 4, 26, 17, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:     "second monday of the month",
+			schedule: nakivo.Schedule{Type: nakivo.ScheduleTypeMonthlyYearly, StartTime: "05:00:00 PM", MonthlyEveryType: nakivo.MonthlyEveryTypeSecond, DayOfWeek: 1},
+			want:     []time.Time{utc(11, 17, 0), time.Date(2024, 4, 8, 17, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:     "last day of february",
+			schedule: nakivo.Schedule{Type: nakivo.ScheduleTypeMonthlyYearly, StartTime: "01:00:00 AM", MonthlyEveryType: nakivo.MonthlyEveryTypeDay, DayOfMonth: 31, Month: 2},
+			want:     []time.Time{time.Date(2025, 2, 28, 1, 0, 0, 0, time.UTC), time.Date(2026, 2, 28, 1, 0, 0, 0, time.UTC), time.Date(2027, 2, 28, 1, 0, 0, 0, time.UTC), time.Date(2028, 2, 29, 1, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:     "effective date",
+			schedule: nakivo.Schedule{Type: nakivo.ScheduleTypeDaily, StartTime: "10:00:00 PM", On: int(nakivo.AllDays), EffectiveDate: "2024-03-10T00:00:00.000Z"},
+			want:     []time.Time{utc(10, 22, 0), utc(11, 22, 0)},
+		},
+		{
+			name:     "trigger",
+			schedule: nakivo.Schedule{Type: nakivo.ScheduleTypeTrigger, TriggerItem: "Job-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := tt.from
+			if start.IsZero() {
+				start = from
+			}
+			want := len(tt.want)
+			if want == 0 {
+				want = 1
+			}
+			assertTimes(t, nextTimes(t, tt.schedule, start, want), tt.want)
+		})
+	}
+}
+
+func TestScheduleNextInvalid(t *testing.T) {
+	for _, schedule := range []nakivo.Schedule{
+		{Type: nakivo.ScheduleTypeDaily},
+		{Type: nakivo.ScheduleTypeDaily, StartTime: "noon"},
+		{Type: nakivo.ScheduleTypePeriodically, StartTime: "01:00:00 AM", EveryType: nakivo.EveryTypeHour},
+		{Type: "HOURLY", StartTime: "01:00:00 AM"},
+	} {
+		if _, _, err := schedule.Next(utc(1, 0, 0)); err == nil {
+			t.Errorf("expected an error for %+v", schedule)
+		}
+	}
+}
+
+func TestJobNextRunsPeriodic(t *testing.T) {
+	schedule := nakivo.Schedule{Enabled: true, Type: nakivo.ScheduleTypePeriodically, StartTime: "11:00:00 PM", EndTime: "01:00:00 AM",
+		Every: 30, EveryType: nakivo.EveryTypeSecond, On: int(nakivo.Weekdays)}
+	job := nakivo.Job{Vid: "Job-1", Name: "often", IsEnabled: true, Schedules: []nakivo.Schedule{schedule}}
+
+	// the runs span several nights, including a weekend without runs
+	runs, err := job.NextRuns(utc(1, 12, 0), 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	times := make([]time.Time, len(runs))
+	for i, run := range runs {
+		times[i] = run.Time
+	}
+	assertTimes(t, times, nextTimes(t, schedule, utc(1, 12, 0), 1000))
+}
+
+func TestJobNextRuns(t *testing.T) {
+	job := nakivo.Job{Vid: "Job-1", Name: "nightly", IsEnabled: true, Schedules: []nakivo.Schedule{
+		{Enabled: true, Position: 1, Type: nakivo.ScheduleTypeDaily, StartTime: "10:00:00 PM", On: int(nakivo.AllDays)},
+		{Enabled: true, Position: 0, Type: nakivo.ScheduleTypeMonthlyYearly, StartTime: "10:00:00 PM", MonthlyEveryType: nakivo.MonthlyEveryTypeFirst, DayOfWeek: 6},
+		{Enabled: false, Position: 2, Type: nakivo.ScheduleTypeDaily, StartTime: "06:00:00 AM", On: int(nakivo.AllDays)},
+	}}
+
+	runs, err := job.NextRuns(utc(1, 12, 0), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	times := make([]time.Time, len(runs))
+	for i, run := range runs {
+		times[i] = run.Time
+		if run.Job != &job {
+			t.Errorf("expected run %d of the job", i)
+		}
+	}
+	assertTimes(t, times, []time.Time{utc(1, 22, 0), utc(2, 22, 0), utc(3, 22, 0)})
+	// the first Saturday is planned by the schedule with the lower position
+	if runs[0].Schedule.Position != 1 || runs[1].Schedule.Position != 0 || runs[2].Schedule.Position != 1 {
+		t.Errorf("unexpected schedules %d, %d and %d", runs[0].Schedule.Position, runs[1].Schedule.Position, runs[2].Schedule.Position)
+	}
+
+	job.IsEnabled = false
+	if runs, err := job.NextRuns(utc(1, 12, 0), 3); err != nil || len(runs) != 0 {
+		t.Errorf("expected no runs of a disabled job, got %d (%v)", len(runs), err)
+	}
+}
+
+func TestPlannerTriggeredRuns(t *testing.T) {
+	jobs := []nakivo.Job{
+		{Vid: "Job-1", Name: "backup", IsEnabled: true, AverageDurationMs: 60 * 60 * 1000, Schedules: []nakivo.Schedule{
+			{Enabled: true, Type: nakivo.ScheduleTypeDaily, StartTime: "10:00:00 PM", On: int(nakivo.AllDays)},
+		}},
+		{Vid: "Job-2", Name: "copy", IsEnabled: true, Schedules: []nakivo.Schedule{
+			{Enabled: true, Type: nakivo.ScheduleTypeTrigger, TriggerItem: "Job-1", TriggerRunType: nakivo.TriggerRunTypeDelayed, Every: 10, EveryType: nakivo.EveryTypeMinute},
+		}},
+		{Vid: "Job-3", Name: "orphan", IsEnabled: true, Schedules: []nakivo.Schedule{
+			{Enabled: true, Type: nakivo.ScheduleTypeTrigger, TriggerItem: "Job-9"},
+		}},
+	}
+	planner := nakivo.NewPlanner(jobs)
+
+	// the run triggered by the run of the day before is still ahead
+	runs, err := planner.NextRuns(&jobs[1], utc(1, 23, 0), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("expected 2 runs, got %d", len(runs))
+	}
+	assertTimes(t, []time.Time{runs[0].Time, runs[1].Time}, []time.Time{utc(1, 23, 10), utc(2, 23, 10)})
+	if runs[0].Trigger == nil || !runs[0].Trigger.Time.Equal(utc(1, 22, 0)) || runs[0].Trigger.Job != &jobs[0] {
+		t.Errorf("unexpected trigger %+v", runs[0].Trigger)
+	}
+
+	// runs triggered by unknown jobs aren't planned, nor by Job.NextRuns
+	if runs, err := planner.NextRuns(&jobs[2], utc(1, 0, 0), 2); err != nil || len(runs) != 0 {
+		t.Errorf("expected no runs of the orphan, got %d (%v)", len(runs), err)
+	}
+	if runs, err := jobs[1].NextRuns(utc(1, 0, 0), 2); err != nil || len(runs) != 0 {
+		t.Errorf("expected no runs without planner, got %d (%v)", len(runs), err)
+	}
+}
+
+func TestPlannerTriggerCycle(t *testing.T) {
+	jobs := []nakivo.Job{
+		{Vid: "Job-1", Name: "a", IsEnabled: true, Schedules: []nakivo.Schedule{{Enabled: true, Type: nakivo.ScheduleTypeTrigger, TriggerItem: "Job-2"}}},
+		{Vid: "Job-2", Name: "b", IsEnabled: true, Schedules: []nakivo.Schedule{{Enabled: true, Type: nakivo.ScheduleTypeTrigger, TriggerItem: "Job-1"}}},
+	}
+	if _, err := nakivo.NewPlanner(jobs).NextRuns(&jobs[0], utc(1, 0, 0), 1); err == nil {
+		t.Error("expected an error for jobs triggering each other")
+	}
+}
@@ -0,0 +1,154 @@
+package nakivo
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// tlsOptions collects the TLS related options until the client is configured.
+type tlsOptions struct {
+	rootCAs            *x509.CertPool
+	fingerprints       [][]byte
+	certificates       []tls.Certificate
+	minVersion         uint16
+	insecureSkipVerify bool
+}
+
+// WithCABundle trusts the PEM encoded CA certificates in pem, in addition to the system roots.
+func WithCABundle(pem []byte) Option {
+	return func(c *Client) error {
+		opts := c.tlsOptions()
+		if opts.rootCAs == nil {
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			opts.rootCAs = pool
+		}
+		if !opts.rootCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in CA bundle")
+		}
+		return nil
+	}
+}
+
+// WithCAFile trusts the PEM encoded CA certificates in the file at path, in addition to the
+// system roots.
+func WithCAFile(path string) Option {
+	return func(c *Client) error {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read CA file failed (%s)", err)
+		}
+		return WithCABundle(pem)(c)
+	}
+}
+
+// WithCertificateFingerprint pins the certificate of the director by the SHA-256 fingerprint
+// of its DER encoding, given in hex with or without colons. May be used several times to
+// accept any of the pinned certificates, e.g. during a certificate rollover.
+//
+// If no CA bundle is configured, the certificate chain is not verified and the pin alone
+// authenticates the director, which suits the self-signed certificates directors ship with.
+func WithCertificateFingerprint(fingerprint string) Option {
+	return func(c *Client) error {
+		fp, err := hex.DecodeString(strings.ReplaceAll(strings.TrimSpace(fingerprint), ":", ""))
+		if err != nil {
+			return fmt.Errorf("invalid certificate fingerprint (%s)", err)
+		}
+		if len(fp) != sha256.Size {
+			return fmt.Errorf("invalid certificate fingerprint (expected %d bytes, got %d)", sha256.Size, len(fp))
+		}
+		opts := c.tlsOptions()
+		opts.fingerprints = append(opts.fingerprints, fp)
+		return nil
+	}
+}
+
+// WithClientCertificate presents cert to directors requiring client certificates.
+func WithClientCertificate(cert tls.Certificate) Option {
+	return func(c *Client) error {
+		opts := c.tlsOptions()
+		opts.certificates = append(opts.certificates, cert)
+		return nil
+	}
+}
+
+// WithMinTLSVersion sets the minimum TLS version, e.g. tls.VersionTLS12.
+func WithMinTLSVersion(version uint16) Option {
+	return func(c *Client) error {
+		c.tlsOptions().minVersion = version
+		return nil
+	}
+}
+
+// WithInsecureSkipVerify disables the verification of the certificate of the director. This
+// makes the connection vulnerable to man in the middle attacks; prefer WithCABundle or
+// WithCertificateFingerprint.
+func WithInsecureSkipVerify() Option {
+	return func(c *Client) error {
+		c.tlsOptions().insecureSkipVerify = true
+		return nil
+	}
+}
+
+func (c *Client) tlsOptions() *tlsOptions {
+	if c.tls == nil {
+		c.tls = &tlsOptions{}
+	}
+	return c.tls
+}
+
+// configureTLS applies the TLS options to transport.
+func (c *Client) configureTLS(transport *http.Transport) {
+	opts := c.tls
+
+	config := transport.TLSClientConfig
+	if config == nil {
+		config = &tls.Config{}
+	}
+	if opts.rootCAs != nil {
+		config.RootCAs = opts.rootCAs
+	}
+	if len(opts.certificates) > 0 {
+		config.Certificates = append(config.Certificates, opts.certificates...)
+	}
+	if opts.minVersion != 0 {
+		config.MinVersion = opts.minVersion
+	}
+	if len(opts.fingerprints) > 0 {
+		if opts.rootCAs == nil {
+			// the pin authenticates the director, the chain is not verified
+			config.InsecureSkipVerify = true
+		}
+		config.VerifyConnection = verifyFingerprint(opts.fingerprints)
+	}
+	if opts.insecureSkipVerify {
+		c.warn("nakivo certificate verification is disabled", "host", c.baseURL.Host)
+		config.InsecureSkipVerify = true
+	}
+	transport.TLSClientConfig = config
+}
+
+// verifyFingerprint returns a connection verifier accepting only leaf certificates matching
+// one of the fingerprints.
+func verifyFingerprint(fingerprints [][]byte) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return fmt.Errorf("no peer certificate presented")
+		}
+		sum := sha256.Sum256(cs.PeerCertificates[0].Raw)
+		for _, fp := range fingerprints {
+			if string(fp) == string(sum[:]) {
+				return nil
+			}
+		}
+		return fmt.Errorf("certificate fingerprint %s is not pinned", hex.EncodeToString(sum[:]))
+	}
+}
@@ -0,0 +1,121 @@
+package nakivo_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"io"
+	"log"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/peertechde/go-nakivo"
+)
+
+// tlsDirector returns a director with a self-signed certificate.
+func tlsDirector(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(router(answerTrue))
+	// rejected certificates are expected
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// fingerprint returns the SHA-256 fingerprint of the certificate of srv, formatted like
+// browsers show it.
+func fingerprint(srv *httptest.Server) string {
+	sum := sha256.Sum256(srv.Certificate().Raw)
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = strings.ToUpper(hex.EncodeToString([]byte{b}))
+	}
+	return strings.Join(parts, ":")
+}
+
+// isLogged calls isLogged with a client of srv configured with opts.
+func isLogged(t *testing.T, srv *httptest.Server, opts ...nakivo.Option) error {
+	t.Helper()
+	client, err := nakivo.NewClient(nil, "", 0, append([]nakivo.Option{nakivo.WithBaseURL(srv.URL)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = client.Authentication.IsLoggedIn(context.Background())
+	return err
+}
+
+func TestSelfSignedCertificate(t *testing.T) {
+	srv := tlsDirector(t)
+	if err := isLogged(t, srv); err == nil {
+		t.Error("expected the self-signed certificate to be rejected")
+	}
+}
+
+func TestCertificateFingerprint(t *testing.T) {
+	srv := tlsDirector(t)
+	other := strings.Repeat("ab", sha256.Size)
+
+	tests := []struct {
+		name         string
+		fingerprints []string
+		ok           bool
+	}{
+		{name: "match", fingerprints: []string{fingerprint(srv)}, ok: true},
+		{name: "match without colons", fingerprints: []string{strings.ReplaceAll(fingerprint(srv), ":", "")}, ok: true},
+		{name: "rollover", fingerprints: []string{other, fingerprint(srv)}, ok: true},
+		{name: "mismatch", fingerprints: []string{other}},
+	}
+	for _, tt := range tests {
+		var opts []nakivo.Option
+		for _, fp := range tt.fingerprints {
+			opts = append(opts, nakivo.WithCertificateFingerprint(fp))
+		}
+		err := isLogged(t, srv, opts...)
+		if tt.ok && err != nil {
+			t.Errorf("%s: unexpected error (%s)", tt.name, err)
+		}
+		if !tt.ok && (err == nil || !strings.Contains(err.Error(), "is not pinned")) {
+			t.Errorf("%s: expected the certificate to be rejected, got %v", tt.name, err)
+		}
+	}
+
+	for _, fp := range []string{"zz", "ab:cd"} {
+		if _, err := nakivo.NewClient(nil, "director", 4443, nakivo.WithCertificateFingerprint(fp)); err == nil {
+			t.Errorf("expected fingerprint %q to be invalid", fp)
+		}
+	}
+}
+
+func TestCABundle(t *testing.T) {
+	srv := tlsDirector(t)
+	bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := isLogged(t, srv, nakivo.WithCABundle(bundle)); err != nil {
+		t.Errorf("unexpected error (%s)", err)
+	}
+
+	// the chain is verified in addition to the pin
+	if err := isLogged(t, srv, nakivo.WithCABundle(bundle), nakivo.WithCertificateFingerprint(strings.Repeat("ab", sha256.Size))); err == nil {
+		t.Error("expected the certificate to be rejected")
+	}
+
+	if _, err := nakivo.NewClient(nil, "director", 4443, nakivo.WithCABundle([]byte("no certificate"))); err == nil {
+		t.Error("expected an empty CA bundle to be rejected")
+	}
+}
+
+func TestInsecureSkipVerify(t *testing.T) {
+	srv := tlsDirector(t)
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	if err := isLogged(t, srv, nakivo.WithInsecureSkipVerify(), nakivo.WithLogger(logger)); err != nil {
+		t.Errorf("unexpected error (%s)", err)
+	}
+	if !strings.Contains(buf.String(), "level=WARN") || !strings.Contains(buf.String(), "certificate verification is disabled") {
+		t.Errorf("expected a warning, got %q", buf.String())
+	}
+}